@@ -0,0 +1,127 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInconsistentReports is returned when reports do not describe changes of the original data.
+	ErrInconsistentReports = errors.New("reports are inconsistent with the original data")
+)
+
+// Apply applies the reports to original and returns the new data.
+// The reports must be ordered like the reports of BytesDiff and BytesDifferent:
+// changed and deleted bytes refer to indexes of original, inserted bytes refer to
+// indexes of the new data. If the reports are inconsistent with original,
+// ErrInconsistentReports is returned, see Reports.Validate.
+func Apply(original []byte, reports Reports) ([]byte, error) {
+	out := make([]byte, 0, len(original)+len(reports))
+	// copied is the index of the first byte of original that has not been copied yet
+	copied := 0
+	err := reports.replay(original, func(report Report, i, j int) {
+		out = append(out, original[copied:i]...)
+		copied = i
+		switch report.Kind {
+		case ReportKindChanged:
+			out = append(out, report.New)
+			copied++
+		case ReportKindDeleted:
+			copied++
+		case ReportKindInserted:
+			out = append(out, report.New)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(out, original[copied:]...), nil
+}
+
+// Revert applies the inverse of the reports to the new data and returns the original data.
+func Revert(new []byte, reports Reports) ([]byte, error) {
+	return Apply(new, reports.Invert())
+}
+
+// Validate returns an error if the reports cannot be applied to original: the reports are
+// out of order, refer to indexes outside of original or the original bytes of changed and
+// deleted reports differ from the bytes in original.
+func (r Reports) Validate(original []byte) error {
+	return r.replay(original, func(Report, int, int) {})
+}
+
+// Invert returns the reports that transform the new data back into the original data.
+// Inserted bytes become deleted bytes and vice versa, the indexes of changed bytes are
+// moved to the positions of the bytes in the new data.
+func (r Reports) Invert() Reports {
+	inverted := make(Reports, len(r))
+	// offset is the number of inserted minus the number of deleted bytes so far
+	offset := 0
+	for i, report := range r {
+		inv := report
+		switch report.Kind {
+		case ReportKindChanged:
+			inv.Index = report.Index + offset
+			new := report.New
+			inv.Original = &new
+			inv.New = 0
+			if report.Original != nil {
+				inv.New = *report.Original
+			}
+		case ReportKindInserted:
+			new := report.New
+			inv.Kind = ReportKindDeleted
+			inv.Original = &new
+			inv.New = 0
+			offset++
+		case ReportKindDeleted:
+			inv.Kind = ReportKindInserted
+			inv.Original = nil
+			inv.New = 0
+			if report.Original != nil {
+				inv.New = *report.Original
+			}
+			offset--
+		}
+		inverted[i] = inv
+	}
+	return inverted
+}
+
+// replay validates the reports against original and calls fn for every report with
+// the index i in original and the index j in the new data the report applies to.
+// fn is not called for reports following an inconsistent report.
+func (r Reports) replay(original []byte, fn func(report Report, i, j int)) error {
+	// i is the index in original, j the index in the new data
+	i, j := 0, 0
+	for n, report := range r {
+		switch report.Kind {
+		case ReportKindChanged, ReportKindDeleted:
+			if report.Index < i || report.Index >= len(original) {
+				return fmt.Errorf("%w: report %d: index %d of %s byte is out of order or range", ErrInconsistentReports, n, report.Index, report.Kind)
+			}
+			if report.Original == nil || *report.Original != original[report.Index] {
+				return fmt.Errorf("%w: report %d: original byte at index %d does not match", ErrInconsistentReports, n, report.Index)
+			}
+			j += report.Index - i
+			i = report.Index
+			fn(report, i, j)
+			i++
+			if report.Kind == ReportKindChanged {
+				j++
+			}
+		case ReportKindInserted:
+			equal := report.Index - j
+			if equal < 0 || i+equal > len(original) {
+				return fmt.Errorf("%w: report %d: index %d of inserted byte is out of order or range", ErrInconsistentReports, n, report.Index)
+			}
+			i += equal
+			j = report.Index
+			fn(report, i, j)
+			j++
+		default:
+			return fmt.Errorf("%w: report %d: unsupported kind %s", ErrInconsistentReports, n, report.Kind)
+		}
+	}
+	return nil
+}
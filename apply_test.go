@@ -0,0 +1,168 @@
+package compare
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	x, y := byte('x'), byte('y')
+	type args struct {
+		original []byte
+		reports  Reports
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "no reports",
+			args: args{
+				original: []byte("abc"),
+				reports:  Reports{},
+			},
+			want:    []byte("abc"),
+			wantErr: nil,
+		},
+		{
+			name: "changed, deleted and inserted",
+			args: args{
+				original: []byte("axcy"),
+				reports: Reports{
+					{Kind: ReportKindChanged, Index: 1, Original: &x, New: 'b'},
+					{Kind: ReportKindDeleted, Index: 3, Original: &y},
+					{Kind: ReportKindInserted, Index: 3, New: 'd'},
+				},
+			},
+			want:    []byte("abcd"),
+			wantErr: nil,
+		},
+		{
+			name: "original byte does not match",
+			args: args{
+				original: []byte("abc"),
+				reports: Reports{
+					{Kind: ReportKindChanged, Index: 1, Original: &x, New: 'y'},
+				},
+			},
+			want:    nil,
+			wantErr: ErrInconsistentReports,
+		},
+		{
+			name: "index out of range",
+			args: args{
+				original: []byte("abc"),
+				reports: Reports{
+					{Kind: ReportKindDeleted, Index: 3, Original: &x},
+				},
+			},
+			want:    nil,
+			wantErr: ErrInconsistentReports,
+		},
+		{
+			name: "reports out of order",
+			args: args{
+				original: []byte("xx"),
+				reports: Reports{
+					{Kind: ReportKindDeleted, Index: 1, Original: &x},
+					{Kind: ReportKindDeleted, Index: 0, Original: &x},
+				},
+			},
+			want:    nil,
+			wantErr: ErrInconsistentReports,
+		},
+		{
+			name: "inserted byte beyond the new data",
+			args: args{
+				original: []byte("a"),
+				reports: Reports{
+					{Kind: ReportKindInserted, Index: 3, New: 'b'},
+				},
+			},
+			want:    nil,
+			wantErr: ErrInconsistentReports,
+		},
+		{
+			name: "equal report",
+			args: args{
+				original: []byte("a"),
+				reports: Reports{
+					{Kind: ReportKindEqual, Index: 0},
+				},
+			},
+			want:    nil,
+			wantErr: ErrInconsistentReports,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.args.original, tt.args.reports)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+			if err := tt.args.reports.Validate(tt.args.original); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reports.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApply_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() []byte {
+		b := make([]byte, rnd.Intn(20))
+		for i := range b {
+			b[i] = byte('a' + rnd.Intn(3))
+		}
+		return b
+	}
+	for n := 0; n < 500; n++ {
+		a, b := random(), random()
+		for _, diff := range []struct {
+			name string
+			fn   func(a, b []byte) (Reports, error)
+		}{
+			{name: "BytesDiff", fn: func(a, b []byte) (Reports, error) { return BytesDiff(a, b), nil }},
+			{name: "BytesDifferent", fn: BytesDifferent},
+		} {
+			reports, err := diff.fn(a, b)
+			if err != nil {
+				t.Fatalf("%s(%q, %q) error = %v", diff.name, a, b, err)
+			}
+			got, err := Apply(a, reports)
+			if err != nil || string(got) != string(b) {
+				t.Fatalf("Apply(%q, %s(%q, %q)) = %q, %v", a, diff.name, a, b, got, err)
+			}
+			got, err = Revert(b, reports)
+			if err != nil || string(got) != string(a) {
+				t.Fatalf("Revert(%q, %s(%q, %q)) = %q, %v", b, diff.name, a, b, got, err)
+			}
+		}
+	}
+}
+
+func TestReports_Invert(t *testing.T) {
+	a, b, c := byte('a'), byte('b'), byte('c')
+	reports := Reports{
+		{Kind: ReportKindInserted, Index: 0, New: 'a'},
+		{Kind: ReportKindChanged, Index: 1, Original: &b, New: 'x'},
+		{Kind: ReportKindDeleted, Index: 2, Original: &c},
+	}
+	x := byte('x')
+	want := Reports{
+		{Kind: ReportKindDeleted, Index: 0, Original: &a},
+		{Kind: ReportKindChanged, Index: 2, Original: &x, New: 'b'},
+		{Kind: ReportKindInserted, Index: 2, New: 'c'},
+	}
+	if got := reports.Invert(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reports.Invert() = %+v, want %+v", got, want)
+	}
+}
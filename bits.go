@@ -0,0 +1,189 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BitOrder defines how the bits of a byte are numbered.
+type BitOrder int
+
+const (
+	// BitOrderMSB numbers the most significant bit as 0, like the header diagrams of RFCs.
+	BitOrderMSB BitOrder = iota
+	// BitOrderLSB numbers the least significant bit as 0, like bit shifts.
+	BitOrderLSB
+)
+
+// BitReports defines the bit-level differences of bytes.
+type BitReports []BitReport
+
+// BitReport describes which bits of a byte differ.
+type BitReport struct {
+	Report
+	// Order defines how Bits are numbered.
+	Order BitOrder
+	// Mask is the XOR of the original and the new byte, every set bit differs.
+	// Inserted and deleted bytes differ completely, their mask is 0xff.
+	Mask byte
+	// Bits are the positions of the differing bits within the byte in ascending order.
+	Bits []int
+}
+
+// Bits returns the bit-level differences of the reports. Bit positions are numbered by order.
+func (r Reports) Bits(order BitOrder) BitReports {
+	reports := make(BitReports, 0, len(r))
+	for _, report := range r {
+		mask := byte(0xff)
+		if report.Kind == ReportKindChanged && report.Original != nil {
+			mask = *report.Original ^ report.New
+		}
+		reports = append(reports, BitReport{Report: report, Order: order, Mask: mask, Bits: bitPositions(mask, order)})
+	}
+	return reports
+}
+
+// BitsDifferent compares a and b index by index like BytesDifferent
+// and returns which bits of the differing bytes changed.
+func BitsDifferent(a, b []byte, order BitOrder) (BitReports, error) {
+	reports, err := BytesDifferent(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return reports.Bits(order), nil
+}
+
+// Offsets returns the positions of the differing bits counted from the first bit
+// of the data, that is Index*8 plus the position within the byte.
+func (r BitReport) Offsets() []int {
+	offsets := make([]int, len(r.Bits))
+	for i, bit := range r.Bits {
+		offsets[i] = r.Index*8 + bit
+	}
+	return offsets
+}
+
+// String returns the report as "byte 3: 01100001 → 01100011 (mask 00000010, bits 6)".
+func (r BitReport) String() string {
+	return fmt.Sprintf("byte %d: %s → %s (mask %08b, bits %s)", r.Index, binaryString(r.old()), binaryString(r.new()), r.Mask, r.bitList())
+}
+
+// bitList returns the differing bit positions separated by commas.
+func (r BitReport) bitList() string {
+	bits := make([]string, len(r.Bits))
+	for i, bit := range r.Bits {
+		bits[i] = strconv.Itoa(bit)
+	}
+	return strings.Join(bits, ",")
+}
+
+// String returns the reports separated by newlines.
+func (r BitReports) String() string {
+	lines := make([]string, len(r))
+	for i, report := range r {
+		lines[i] = report.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// old returns the original byte or nil if the byte was inserted.
+func (r BitReport) old() *byte {
+	if r.Kind == ReportKindInserted {
+		return nil
+	}
+	return r.Original
+}
+
+// new returns the new byte or nil if the byte was deleted.
+func (r BitReport) new() *byte {
+	if r.Kind == ReportKindDeleted {
+		return nil
+	}
+	new := r.New
+	return &new
+}
+
+// bitPositions returns the positions of the set bits of mask numbered by order.
+func bitPositions(mask byte, order BitOrder) []int {
+	bits := []int{}
+	for bit := 0; bit < 8; bit++ {
+		if mask&bitMask(bit, order) != 0 {
+			bits = append(bits, bit)
+		}
+	}
+	return bits
+}
+
+// bitMask returns the mask of the bit at position bit numbered by order.
+func bitMask(bit int, order BitOrder) byte {
+	if order == BitOrderLSB {
+		return 1 << uint(bit)
+	}
+	return 0x80 >> uint(bit)
+}
+
+// binaryString returns b as eight binary digits, most significant bit first.
+// A nil byte is written as "--------".
+func binaryString(b *byte) string {
+	if b == nil {
+		return "--------"
+	}
+	return fmt.Sprintf("%08b", *b)
+}
+
+// BitRenderOptions configures the bit renderer.
+type BitRenderOptions struct {
+	// Color defines whether ANSI colors are used to mark differing bits.
+	Color ColorMode
+	// Order defines how bit positions are numbered.
+	Order BitOrder
+}
+
+// RenderBitDiff compares a and b index by index and writes the differing bytes as binary strings to w,
+// see RenderBits.
+func RenderBitDiff(w io.Writer, a, b []byte, opts BitRenderOptions) error {
+	reports, err := BitsDifferent(a, b, opts.Order)
+	if err != nil {
+		return err
+	}
+	return RenderBits(w, reports, opts.Color)
+}
+
+// RenderBits writes one row per report to w with the offset, the original and the new byte
+// as binary strings, the XOR mask and the differing bit positions:
+//
+//	00000003: 01100001 → 01100011  mask 00000010  bits 6
+//
+// Binary strings are always written most significant bit first. Differing bits are colored
+// if colors are used, missing bytes of inserted and deleted reports are written as "--------".
+func RenderBits(w io.Writer, reports BitReports, color ColorMode) error {
+	useColor := RenderOptions{Color: color}.useColor(w)
+	out := strings.Builder{}
+	for _, report := range reports {
+		fmt.Fprintf(&out, "%08x: %s → %s  mask %08b  bits %s\n", report.Index,
+			styledBinary(report.old(), report.Mask, useColor, ansiDeletedHighlight),
+			styledBinary(report.new(), report.Mask, useColor, ansiInsertedHighlight),
+			report.Mask, report.bitList())
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// styledBinary returns b as binary string and highlights the bits set in mask if color is true.
+func styledBinary(b *byte, mask byte, color bool, highlight string) string {
+	digits := binaryString(b)
+	if !color {
+		return digits
+	}
+	out := strings.Builder{}
+	for i := range digits {
+		if mask&bitMask(i, BitOrderMSB) != 0 {
+			out.WriteString(highlight + digits[i:i+1] + ansiReset)
+			continue
+		}
+		out.WriteByte(digits[i])
+	}
+	return out.String()
+}
@@ -0,0 +1,168 @@
+package compare
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBitsDifferent(t *testing.T) {
+	type args struct {
+		a     []byte
+		b     []byte
+		order BitOrder
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantMask []byte
+		wantBits [][]int
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: []byte{0x45, 0x00},
+				b: []byte{0x45, 0x00},
+			},
+			wantMask: []byte{},
+			wantBits: [][]int{},
+		},
+		{
+			name: "msb order",
+			args: args{
+				a:     []byte{0x45, 0x00},
+				b:     []byte{0x45, 0x81},
+				order: BitOrderMSB,
+			},
+			wantMask: []byte{0x81},
+			wantBits: [][]int{{0, 7}},
+		},
+		{
+			name: "lsb order",
+			args: args{
+				a:     []byte{0x45, 0x00},
+				b:     []byte{0x45, 0x06},
+				order: BitOrderLSB,
+			},
+			wantMask: []byte{0x06},
+			wantBits: [][]int{{1, 2}},
+		},
+		{
+			name: "inserted byte differs completely",
+			args: args{
+				a:     []byte{0x01},
+				b:     []byte{0x03, 0x00},
+				order: BitOrderMSB,
+			},
+			wantMask: []byte{0x02, 0xff},
+			wantBits: [][]int{{6}, {0, 1, 2, 3, 4, 5, 6, 7}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BitsDifferent(tt.args.a, tt.args.b, tt.args.order)
+			if err != nil {
+				t.Fatalf("BitsDifferent() error = %v", err)
+			}
+			masks, bits := []byte{}, [][]int{}
+			for _, report := range got {
+				if report.Order != tt.args.order {
+					t.Errorf("BitsDifferent() order = %v, want %v", report.Order, tt.args.order)
+				}
+				masks = append(masks, report.Mask)
+				bits = append(bits, report.Bits)
+			}
+			if !reflect.DeepEqual(masks, tt.wantMask) {
+				t.Errorf("BitsDifferent() masks = %08b, want %08b", masks, tt.wantMask)
+			}
+			if !reflect.DeepEqual(bits, tt.wantBits) {
+				t.Errorf("BitsDifferent() bits = %v, want %v", bits, tt.wantBits)
+			}
+		})
+	}
+}
+
+func TestBitReport_Offsets(t *testing.T) {
+	reports, err := BitsDifferent([]byte{0x00, 0x00, 0x00}, []byte{0x00, 0x00, 0x11}, BitOrderLSB)
+	if err != nil {
+		t.Fatalf("BitsDifferent() error = %v", err)
+	}
+	if got, want := reports[0].Offsets(), []int{16, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BitReport.Offsets() = %v, want %v", got, want)
+	}
+}
+
+func TestBitReports_String(t *testing.T) {
+	reports, err := BitsDifferent([]byte("abc"), []byte("abcd"), BitOrderMSB)
+	if err != nil {
+		t.Fatalf("BitsDifferent() error = %v", err)
+	}
+	reports = append(Reports{{Kind: ReportKindDeleted, Index: 0, Original: bytePtr('a')}}.Bits(BitOrderMSB), reports...)
+	want := "byte 0: 01100001 → -------- (mask 11111111, bits 0,1,2,3,4,5,6,7)\n" +
+		"byte 3: -------- → 01100100 (mask 11111111, bits 0,1,2,3,4,5,6,7)"
+	if got := reports.String(); got != want {
+		t.Errorf("BitReports.String() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderBitDiff(t *testing.T) {
+	type args struct {
+		a    []byte
+		b    []byte
+		opts BitRenderOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "equal",
+			args: args{
+				a:    []byte("abc"),
+				b:    []byte("abc"),
+				opts: BitRenderOptions{Color: ColorNever},
+			},
+			want: "",
+		},
+		{
+			name: "deleted byte",
+			args: args{
+				a:    []byte("abcd"),
+				b:    []byte("abc"),
+				opts: BitRenderOptions{Color: ColorNever},
+			},
+			want: "00000003: 01100100 → --------  mask 11111111  bits 0,1,2,3,4,5,6,7\n",
+		},
+		{
+			name: "lsb order",
+			args: args{
+				a:    []byte("a"),
+				b:    []byte("c"),
+				opts: BitRenderOptions{Color: ColorNever, Order: BitOrderLSB},
+			},
+			want: "00000000: 01100001 → 01100011  mask 00000010  bits 1\n",
+		},
+		{
+			name: "colored bits",
+			args: args{
+				a:    []byte("a"),
+				b:    []byte("c"),
+				opts: BitRenderOptions{Color: ColorAlways},
+			},
+			want: "00000000: 011000" + ansiDeletedHighlight + "0" + ansiReset + "1 → 011000" +
+				ansiInsertedHighlight + "1" + ansiReset + "1  mask 00000010  bits 6\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			if err := RenderBitDiff(&buf, tt.args.a, tt.args.b, tt.args.opts); err != nil {
+				t.Fatalf("RenderBitDiff() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("RenderBitDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
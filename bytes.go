@@ -12,6 +12,8 @@ var (
 
 // bytesDifferent returns the difference between a and b.
 // a must be smaller than b
+// Every index of a with a different value in b is reported as changed,
+// every index of b that exceeds a is reported as inserted.
 func bytesDifferent(a, b []byte) (Reports, error) {
 	if len(b) < len(a) {
 		return nil, fmt.Errorf("%w: a=%v, b=%v", ErrAExceedsRangeOfB, a, b)
@@ -21,12 +23,14 @@ func bytesDifferent(a, b []byte) (Reports, error) {
 		rsp := ByteDifferent(value, b[idx])
 		if rsp != nil {
 			rsp.Index = idx
+			different = append(different, *rsp)
 		}
 	}
 
 	for idx := len(a); idx < len(b); idx++ {
 		different = append(different, Report{
 			Type:     reflect.TypeOf(b[idx]).String(),
+			Kind:     ReportKindInserted,
 			Index:    idx,
 			Original: nil,
 			New:      b[idx],
@@ -36,7 +40,7 @@ func bytesDifferent(a, b []byte) (Reports, error) {
 	return different, nil
 }
 
-// ByteDifferent returns the difference between a and b.+
+// ByteDifferent returns the difference between a and b.
 // If a and b are the same, nil is returned.
 func ByteDifferent(a, b byte) *Report {
 	if a != b {
@@ -51,15 +55,56 @@ func ByteDifferent(a, b byte) *Report {
 }
 
 // BytesDifferent returns the difference between a and b.
+// The bytes are compared index by index: a is the original and b the new data.
+// Indexes with different values are reported as changed, bytes that only exist
+// in b are reported as inserted and bytes that only exist in a as deleted.
 func BytesDifferent(a, b []byte) (Reports, error) {
-	deferBts, err := bytesDifferent(b, a)
+	if len(a) <= len(b) {
+		return bytesDifferent(a, b)
+	}
+	reports, err := bytesDifferent(b, a)
 	if err != nil {
-		reports, err := BytesDifferent(b, a)
-		if err != nil {
-			return nil, err
-		}
-		reports.swap()
-		return reports, nil
+		return nil, err
 	}
-	return deferBts, nil
+	reports.swap()
+	return reports, nil
+}
+
+// BytesEditScript returns the shortest edit script that transforms a into b.
+// Unlike BytesDifferent, the edit script aligns a and b, so a single inserted byte
+// does not mark all following bytes as changed.
+func BytesEditScript(a, b []byte) EditScript {
+	return DiffSequence(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+}
+
+// BytesDiff returns the difference between a and b based on the shortest edit script.
+// a is the original and b the new data.
+func BytesDiff(a, b []byte) Reports {
+	return BytesEditScript(a, b).Reports(a, b)
+}
+
+// Reports converts the edit script of a and b into reports.
+// Equal runs are omitted. Deleted bytes that are directly followed by inserted bytes
+// are reported as changed: the index of a changed or deleted byte refers to a,
+// the index of an inserted byte refers to b.
+func (s EditScript) Reports(a, b []byte) Reports {
+	reports := Reports{}
+	typ := reflect.TypeOf(byte(0)).String()
+	s.walkReports(func(kind ReportKind, i, j int) {
+		report := Report{Type: typ, Kind: kind}
+		switch kind {
+		case ReportKindChanged:
+			orig := a[i]
+			report.Index, report.Original, report.New = i, &orig, b[j]
+		case ReportKindDeleted:
+			orig := a[i]
+			report.Index, report.Original = i, &orig
+		case ReportKindInserted:
+			report.Index, report.New = j, b[j]
+		}
+		reports = append(reports, report)
+	})
+	return reports
 }
@@ -25,6 +25,14 @@ func Test_bytesDifferent(t *testing.T) {
 			want: Reports{
 				{
 					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte(3); return &b }(),
+					New:      2,
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
 					Index:    5,
 					Original: nil,
 					New:      6,
@@ -136,7 +144,7 @@ func Test_BytesDifferent(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "a must be smaller than b",
+			name: "a bigger than b",
 			args: args{
 				a: []byte{1, 3, 3, 4, 5, 6},
 				b: []byte{1, 2, 3, 4, 5},
@@ -144,13 +152,119 @@ func Test_BytesDifferent(t *testing.T) {
 			want: Reports{
 				{
 					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte(3); return &b }(),
+					New:      2,
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindDeleted,
 					Index:    5,
+					Original: func() *byte { b := byte(6); return &b }(),
+					New:      0,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "a smaller than b",
+			args: args{
+				a: []byte{1, 2},
+				b: []byte{1, 3, 4},
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte(2); return &b }(),
+					New:      3,
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
+					Index:    2,
 					Original: nil,
+					New:      4,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "equal length and equal content",
+			args: args{
+				a: []byte{1, 2, 3},
+				b: []byte{1, 2, 3},
+			},
+			want:    Reports{},
+			wantErr: false,
+		},
+		{
+			name: "equal length and completely different content",
+			args: args{
+				a: []byte{1, 2, 3},
+				b: []byte{4, 5, 6},
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    0,
+					Original: func() *byte { b := byte(1); return &b }(),
+					New:      4,
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte(2); return &b }(),
+					New:      5,
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    2,
+					Original: func() *byte { b := byte(3); return &b }(),
 					New:      6,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "equal length with single difference",
+			args: args{
+				a: []byte("hello"),
+				b: []byte("hallo"),
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte('e'); return &b }(),
+					New:      'a',
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty a",
+			args: args{
+				a: []byte{},
+				b: []byte{7},
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
+					Index:    0,
+					Original: nil,
+					New:      7,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -165,3 +279,117 @@ func Test_BytesDifferent(t *testing.T) {
 		})
 	}
 }
+
+func TestBytesDiff(t *testing.T) {
+	type args struct {
+		a []byte
+		b []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want Reports
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: []byte("hello"),
+				b: []byte("hello"),
+			},
+			want: Reports{},
+		},
+		{
+			name: "inserted byte at the start",
+			args: args{
+				a: []byte("hello"),
+				b: []byte("_hello"),
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
+					Index:    0,
+					Original: nil,
+					New:      '_',
+				},
+			},
+		},
+		{
+			name: "deleted byte at the start",
+			args: args{
+				a: []byte("_hello"),
+				b: []byte("hello"),
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindDeleted,
+					Index:    0,
+					Original: func() *byte { b := byte('_'); return &b }(),
+					New:      0,
+				},
+			},
+		},
+		{
+			name: "replaced bytes are reported as changed",
+			args: args{
+				a: []byte("hello"),
+				b: []byte("hallo!"),
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte('e'); return &b }(),
+					New:      'a',
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
+					Index:    5,
+					Original: nil,
+					New:      '!',
+				},
+			},
+		},
+		{
+			name: "replacement with different length",
+			args: args{
+				a: []byte("a12b"),
+				b: []byte("a345b"),
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: func() *byte { b := byte('1'); return &b }(),
+					New:      '3',
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindChanged,
+					Index:    2,
+					Original: func() *byte { b := byte('2'); return &b }(),
+					New:      '4',
+				},
+				{
+					Type:     "uint8",
+					Kind:     ReportKindInserted,
+					Index:    3,
+					Original: nil,
+					New:      '5',
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BytesDiff(tt.args.a, tt.args.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BytesDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
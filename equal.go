@@ -0,0 +1,322 @@
+package compare
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EqualOptions configures the equality engine used by DeepEqual.
+// The zero value behaves like reflect.DeepEqual.
+//
+// Paths address values from the root of the compared values: struct fields and
+// map keys are separated by dots and slice or array indices are written in brackets,
+// e.g. "users[2].email". In IgnorePaths "*" matches any field or key and "[*]" matches any index.
+type EqualOptions struct {
+	// IgnoreFields defines struct field names and map keys that are ignored at any depth.
+	IgnoreFields []string
+	// IgnorePaths defines paths that are ignored, e.g. "metadata.createdAt" or "users[*].id".
+	IgnorePaths []string
+	// IgnoreSliceOrder compares slices and arrays as multisets.
+	// Every element of a must be equal to a distinct element of b.
+	IgnoreSliceOrder bool
+	// NilEqualsEmpty treats nil pointers, nil interfaces, nil and empty slices as well as nil and empty maps as equal.
+	NilEqualsEmpty bool
+	// NumericTolerance defines the maximum absolute difference of two numbers that are considered equal.
+	NumericTolerance float64
+	// IgnoreUnexported skips unexported struct fields.
+	IgnoreUnexported bool
+	// UseEqualMethod uses the "Equal(T) bool" method of a type if it is available, e.g. time.Time.
+	UseEqualMethod bool
+}
+
+// DeepEqual reports whether a and b are deeply equal according to the options.
+func DeepEqual(a, b interface{}, opts EqualOptions) bool {
+	state := newEqualState(opts)
+	return state.equal("", reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// equalVisit identifies a pair of compared references to detect cycles.
+type equalVisit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+// equalState holds the compiled options and the visited references of a single comparison.
+type equalState struct {
+	opts         EqualOptions
+	ignoreFields map[string]bool
+	ignorePaths  []*regexp.Regexp
+	visited      map[equalVisit]bool
+}
+
+// newEqualState compiles the options.
+func newEqualState(opts EqualOptions) *equalState {
+	s := &equalState{
+		opts:         opts,
+		ignoreFields: make(map[string]bool, len(opts.IgnoreFields)),
+		visited:      map[equalVisit]bool{},
+	}
+	for _, field := range opts.IgnoreFields {
+		s.ignoreFields[field] = true
+	}
+	for _, path := range opts.IgnorePaths {
+		s.ignorePaths = append(s.ignorePaths, compilePathPattern(path))
+	}
+	return s
+}
+
+// compilePathPattern converts a path pattern into a regular expression.
+func compilePathPattern(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\[\*\]`, `\[\d+\]`)
+	expr = strings.ReplaceAll(expr, `\*`, `[^.\[]+`)
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// ignored reports whether the path is ignored.
+func (s *equalState) ignored(path string) bool {
+	for _, rx := range s.ignorePaths {
+		if rx.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// visit runs fn unless the pair of references is already being compared further up
+// the stack. A pair that is revisited is part of a cycle and considered equal.
+func (s *equalState) visit(a, b reflect.Value, fn func() bool) bool {
+	v := equalVisit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+	if s.visited[v] {
+		return true
+	}
+	s.visited[v] = true
+	defer delete(s.visited, v)
+	return fn()
+}
+
+// equal compares a and b located at path.
+func (s *equalState) equal(path string, a, b reflect.Value) bool {
+	if s.ignored(path) {
+		return true
+	}
+	if s.opts.NilEqualsEmpty && isNilOrEmpty(a) && isNilOrEmpty(b) {
+		return true
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	if s.opts.UseEqualMethod {
+		if result, ok := callEqualMethod(a, b); ok {
+			return result
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return s.visit(a, b, func() bool { return s.equal(path, a.Elem(), b.Elem()) })
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return s.equal(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if (field.PkgPath != "" && s.opts.IgnoreUnexported) || s.ignoreFields[field.Name] {
+				continue
+			}
+			if !s.equal(joinPath(path, field.Name), a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		return s.visit(a, b, func() bool { return s.mapEqual(path, a, b) })
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Len() == b.Len() && (a.Len() == 0 || a.Pointer() == b.Pointer()) {
+			return true
+		}
+		return s.visit(a, b, func() bool { return s.sequenceEqual(path, a, b) })
+	case reflect.Array:
+		return s.sequenceEqual(path, a, b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int() || s.numberEqual(float64(a.Int()), float64(b.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint() || s.numberEqual(float64(a.Uint()), float64(b.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return s.numberEqual(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Func:
+		return a.IsNil() && b.IsNil()
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return false
+}
+
+// mapEqual compares the entries of the maps a and b.
+func (s *equalState) mapEqual(path string, a, b reflect.Value) bool {
+	iter := a.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key())
+		if s.ignoreFields[key] || s.ignored(joinPath(path, key)) {
+			continue
+		}
+		bv := b.MapIndex(iter.Key())
+		if !bv.IsValid() || !s.equal(joinPath(path, key), iter.Value(), bv) {
+			return false
+		}
+	}
+	iter = b.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key())
+		if s.ignoreFields[key] || s.ignored(joinPath(path, key)) {
+			continue
+		}
+		if !a.MapIndex(iter.Key()).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// sequenceEqual compares the elements of the slices or arrays a and b.
+func (s *equalState) sequenceEqual(path string, a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	if s.opts.IgnoreSliceOrder {
+		return s.unorderedEqual(path, a, b)
+	}
+	for i := 0; i < a.Len(); i++ {
+		if !s.equal(indexPath(path, i), a.Index(i), b.Index(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// unorderedEqual reports whether every element of a can be paired with a distinct equal element
+// of b. Equality is not transitive with a numeric tolerance or ignored paths, so the first equal
+// element is not always the right partner: the pairs are found as a maximum bipartite matching
+// with augmenting paths.
+func (s *equalState) unorderedEqual(path string, a, b reflect.Value) bool {
+	n := a.Len()
+	// candidates holds the indexes of the elements of b that are equal to the element of a
+	candidates := make([][]int, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if s.equal(indexPath(path, i), a.Index(i), b.Index(j)) {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+		if len(candidates[i]) == 0 {
+			return false
+		}
+	}
+	// pairedWith holds the index of the element of a paired with the element of b, or -1
+	pairedWith := make([]int, n)
+	for j := range pairedWith {
+		pairedWith[j] = -1
+	}
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for _, j := range candidates[i] {
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			if pairedWith[j] < 0 || augment(pairedWith[j], seen) {
+				pairedWith[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !augment(i, make([]bool, n)) {
+			return false
+		}
+	}
+	return true
+}
+
+// numberEqual reports whether the difference of a and b is within the tolerance.
+func (s *equalState) numberEqual(a, b float64) bool {
+	return a == b || (s.opts.NumericTolerance > 0 && math.Abs(a-b) <= s.opts.NumericTolerance)
+}
+
+// isNilOrEmpty reports whether v is invalid, a nil pointer or interface, or an empty slice or map.
+func isNilOrEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// callEqualMethod calls the "Equal(T) bool" method of a with b as argument.
+// The second return value is false if a has no such method.
+func callEqualMethod(a, b reflect.Value) (bool, bool) {
+	if !a.CanInterface() || !b.CanInterface() {
+		return false, false
+	}
+	method := a.MethodByName("Equal")
+	if !method.IsValid() {
+		return false, false
+	}
+	mt := method.Type()
+	if mt.NumIn() != 1 || mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool || !b.Type().AssignableTo(mt.In(0)) {
+		return false, false
+	}
+	if a.Kind() == reflect.Ptr && (a.IsNil() || b.IsNil()) {
+		return a.IsNil() == b.IsNil(), true
+	}
+	return method.Call([]reflect.Value{b})[0].Bool(), true
+}
+
+// joinPath appends the field or key name to the path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indexPath appends the index to the path.
+func indexPath(path string, idx int) string {
+	return path + "[" + strconv.Itoa(idx) + "]"
+}
@@ -0,0 +1,279 @@
+package compare
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type equalTestUser struct {
+	Name      string
+	Email     string
+	CreatedAt time.Time
+	Tags      []string
+	Labels    map[string]string
+	secret    string
+}
+
+type equalTestNode struct {
+	Value int
+	Next  *equalTestNode
+}
+
+type equalTestVersion struct {
+	Major, Minor int
+	Raw          string
+}
+
+// Equal compares the versions by their numeric parts only.
+func (v equalTestVersion) Equal(o equalTestVersion) bool {
+	return v.Major == o.Major && v.Minor == o.Minor
+}
+
+func TestDeepEqual(t *testing.T) {
+	now := time.Date(2022, 3, 5, 12, 0, 0, 0, time.UTC)
+	cycleA := &equalTestNode{Value: 1}
+	cycleA.Next = cycleA
+	cycleB := &equalTestNode{Value: 1}
+	cycleB.Next = cycleB
+	cycleC := &equalTestNode{Value: 2}
+	cycleC.Next = cycleC
+
+	type args struct {
+		a    interface{}
+		b    interface{}
+		opts EqualOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "zero options equal structs",
+			args: args{
+				a: equalTestUser{Name: "a", Tags: []string{"x"}},
+				b: equalTestUser{Name: "a", Tags: []string{"x"}},
+			},
+			want: true,
+		},
+		{
+			name: "zero options different types",
+			args: args{
+				a: int64(1),
+				b: int32(1),
+			},
+			want: false,
+		},
+		{
+			name: "zero options compares unexported fields",
+			args: args{
+				a: equalTestUser{Name: "a", secret: "1"},
+				b: equalTestUser{Name: "a", secret: "2"},
+			},
+			want: false,
+		},
+		{
+			name: "ignore unexported fields",
+			args: args{
+				a:    equalTestUser{Name: "a", secret: "1"},
+				b:    equalTestUser{Name: "a", secret: "2"},
+				opts: EqualOptions{IgnoreUnexported: true},
+			},
+			want: true,
+		},
+		{
+			name: "ignore fields at any depth",
+			args: args{
+				a: map[string]interface{}{
+					"user": equalTestUser{Name: "a", Email: "a@x"},
+					"id":   1,
+				},
+				b: map[string]interface{}{
+					"user": equalTestUser{Name: "a", Email: "b@x"},
+					"id":   2,
+				},
+				opts: EqualOptions{IgnoreFields: []string{"Email", "id"}},
+			},
+			want: true,
+		},
+		{
+			name: "ignore paths with wildcard index",
+			args: args{
+				a: []equalTestUser{
+					{Name: "a", Email: "a@x"},
+					{Name: "b", Email: "b@x"},
+				},
+				b: []equalTestUser{
+					{Name: "a", Email: "c@x"},
+					{Name: "b", Email: "d@x"},
+				},
+				opts: EqualOptions{IgnorePaths: []string{"[*].Email"}},
+			},
+			want: true,
+		},
+		{
+			name: "ignore paths does not ignore other fields",
+			args: args{
+				a: []equalTestUser{
+					{Name: "a", Email: "a@x"},
+				},
+				b: []equalTestUser{
+					{Name: "b", Email: "a@x"},
+				},
+				opts: EqualOptions{IgnorePaths: []string{"[*].Email"}},
+			},
+			want: false,
+		},
+		{
+			name: "ignore paths with wildcard key",
+			args: args{
+				a: map[string]interface{}{
+					"meta": map[string]interface{}{"created": 1, "updated": 2, "name": "a"},
+				},
+				b: map[string]interface{}{
+					"meta": map[string]interface{}{"created": 3, "name": "a"},
+				},
+				opts: EqualOptions{IgnorePaths: []string{"*.created", "*.updated"}},
+			},
+			want: true,
+		},
+		{
+			name: "slice order matters by default",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"c", "b", "a"},
+			},
+			want: false,
+		},
+		{
+			name: "ignore slice order",
+			args: args{
+				a:    []string{"a", "b", "c"},
+				b:    []string{"c", "b", "a"},
+				opts: EqualOptions{IgnoreSliceOrder: true},
+			},
+			want: true,
+		},
+		{
+			name: "ignore slice order with duplicates",
+			args: args{
+				a:    []string{"a", "a", "b"},
+				b:    []string{"a", "b", "b"},
+				opts: EqualOptions{IgnoreSliceOrder: true},
+			},
+			want: false,
+		},
+		{
+			name: "ignore slice order pairs elements within the tolerance",
+			args: args{
+				a:    []float64{1.0, 1.5},
+				b:    []float64{1.4, 0.6},
+				opts: EqualOptions{IgnoreSliceOrder: true, NumericTolerance: 0.5},
+			},
+			want: true,
+		},
+		{
+			name: "ignore slice order without a distinct pairing",
+			args: args{
+				a:    []float64{1.0, 1.1},
+				b:    []float64{1.4, 3.0},
+				opts: EqualOptions{IgnoreSliceOrder: true, NumericTolerance: 0.5},
+			},
+			want: false,
+		},
+		{
+			name: "nil and empty slice differ by default",
+			args: args{
+				a: equalTestUser{Tags: nil},
+				b: equalTestUser{Tags: []string{}},
+			},
+			want: false,
+		},
+		{
+			name: "nil equals empty",
+			args: args{
+				a:    equalTestUser{Tags: nil, Labels: map[string]string{}},
+				b:    equalTestUser{Tags: []string{}, Labels: nil},
+				opts: EqualOptions{NilEqualsEmpty: true},
+			},
+			want: true,
+		},
+		{
+			name: "numeric tolerance within range",
+			args: args{
+				a:    []float64{1.0, 2.0},
+				b:    []float64{1.05, 1.96},
+				opts: EqualOptions{NumericTolerance: 0.1},
+			},
+			want: true,
+		},
+		{
+			name: "numeric tolerance exceeded",
+			args: args{
+				a:    int64(100),
+				b:    int64(102),
+				opts: EqualOptions{NumericTolerance: 1},
+			},
+			want: false,
+		},
+		{
+			name: "NaN is never equal",
+			args: args{
+				a:    math.NaN(),
+				b:    math.NaN(),
+				opts: EqualOptions{NumericTolerance: 1},
+			},
+			want: false,
+		},
+		{
+			name: "time without equal method",
+			args: args{
+				a: now,
+				b: now.In(time.FixedZone("CET", 3600)),
+			},
+			want: false,
+		},
+		{
+			name: "time with equal method",
+			args: args{
+				a:    equalTestUser{CreatedAt: now},
+				b:    equalTestUser{CreatedAt: now.In(time.FixedZone("CET", 3600))},
+				opts: EqualOptions{UseEqualMethod: true},
+			},
+			want: true,
+		},
+		{
+			name: "custom equal method",
+			args: args{
+				a:    &equalTestVersion{Major: 1, Minor: 2, Raw: "v1.2"},
+				b:    &equalTestVersion{Major: 1, Minor: 2, Raw: "1.2.0"},
+				opts: EqualOptions{UseEqualMethod: true},
+			},
+			want: true,
+		},
+		{
+			name: "cyclic values",
+			args: args{
+				a: cycleA,
+				b: cycleB,
+			},
+			want: true,
+		},
+		{
+			name: "different cyclic values",
+			args: args{
+				a: cycleA,
+				b: cycleC,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeepEqual(tt.args.a, tt.args.b, tt.args.opts); got != tt.want {
+				t.Errorf("DeepEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrBadGlobPattern is returned when a glob pattern is malformed.
+	ErrBadGlobPattern = errors.New("syntax error in glob pattern")
+)
+
+// globMatch reports whether name matches the shell-style glob pattern.
+// The pattern syntax is:
+// - '*': matches any sequence of characters except '/'
+// - '**': matches any sequence of characters including '/'
+// - '?': matches any single character except '/'
+// - '[a-z]': matches a single character of the class, '[!a-z]' or '[^a-z]' negates it
+// - '\c': matches the character c literally
+// A '**' that forms a whole path segment ("a/**/b") also matches zero segments ("a/b").
+func globMatch(pattern, name string) (bool, error) {
+	p := []rune(pattern)
+	if err := validateGlob(p); err != nil {
+		return false, fmt.Errorf("%w: %q", err, pattern)
+	}
+	return matchGlob(p, []rune(name)), nil
+}
+
+// validateGlob checks the pattern for unterminated classes and escapes.
+func validateGlob(p []rune) error {
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			if i+1 >= len(p) {
+				return ErrBadGlobPattern
+			}
+			i++
+		case '[':
+			_, width, ok := matchClass(p[i:], 0)
+			if !ok {
+				return ErrBadGlobPattern
+			}
+			i += width - 1
+		}
+	}
+	return nil
+}
+
+// matchGlob matches the already validated pattern p against s.
+// Results are memoized per pattern and name position, so patterns with many stars
+// take O(len(p)*len(s)) steps instead of backtracking exponentially.
+func matchGlob(p, s []rune) bool {
+	m := globMatcher{p: p, s: s, memo: make([]int8, (len(p)+1)*(len(s)+1))}
+	return m.match(0, 0)
+}
+
+// globMatcher holds the state of a single glob match.
+type globMatcher struct {
+	p, s []rune
+	// memo holds the result of every position pair: 0 unknown, 1 match, 2 mismatch
+	memo []int8
+}
+
+// match reports whether p[pi:] matches s[si:].
+func (m *globMatcher) match(pi, si int) bool {
+	key := pi*(len(m.s)+1) + si
+	if result := m.memo[key]; result != 0 {
+		return result == 1
+	}
+	matched := m.matchAt(pi, si)
+	m.memo[key] = 2
+	if matched {
+		m.memo[key] = 1
+	}
+	return matched
+}
+
+// matchAt evaluates the pattern element at pi against s[si:].
+func (m *globMatcher) matchAt(pi, si int) bool {
+	p, s := m.p, m.s
+	if pi == len(p) {
+		return si == len(s)
+	}
+	switch p[pi] {
+	case '*':
+		if pi+1 < len(p) && p[pi+1] == '*' {
+			rest := pi + 2
+			for rest < len(p) && p[rest] == '*' {
+				rest++
+			}
+			// "**/" may also match zero path segments
+			if rest < len(p) && p[rest] == '/' && m.match(rest+1, si) {
+				return true
+			}
+			return m.match(rest, si) || (si < len(s) && m.match(pi, si+1))
+		}
+		return m.match(pi+1, si) || (si < len(s) && s[si] != '/' && m.match(pi, si+1))
+	case '?':
+		return si < len(s) && s[si] != '/' && m.match(pi+1, si+1)
+	case '[':
+		if si == len(s) || s[si] == '/' {
+			return false
+		}
+		matched, width, _ := matchClass(p[pi:], s[si])
+		return matched && m.match(pi+width, si+1)
+	case '\\':
+		return si < len(s) && s[si] == p[pi+1] && m.match(pi+2, si+1)
+	default:
+		return si < len(s) && s[si] == p[pi] && m.match(pi+1, si+1)
+	}
+}
+
+// matchClass evaluates the character class at the start of p against c.
+// It returns whether c is part of the class, the width of the class in p and
+// whether the class is well-formed.
+func matchClass(p []rune, c rune) (matched bool, width int, ok bool) {
+	i := 1
+	negate := false
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		negate = true
+		i++
+	}
+	first := true
+	for i < len(p) {
+		if p[i] == ']' && !first {
+			return matched != negate, i + 1, true
+		}
+		first = false
+		lo, n, ok := classChar(p[i:])
+		if !ok {
+			return false, 0, false
+		}
+		i += n
+		hi := lo
+		if i+1 < len(p) && p[i] == '-' && p[i+1] != ']' {
+			hi, n, ok = classChar(p[i+1:])
+			if !ok || hi < lo {
+				return false, 0, false
+			}
+			i += n + 1
+		}
+		if lo <= c && c <= hi {
+			matched = true
+		}
+	}
+	return false, 0, false
+}
+
+// classChar returns the (possibly escaped) character at the start of p and its width.
+func classChar(p []rune) (rune, int, bool) {
+	if p[0] == '\\' {
+		if len(p) < 2 {
+			return 0, 0, false
+		}
+		return p[1], 2, true
+	}
+	return p[0], 1, true
+}
+
+// globMatchFold is the case-insensitive variant of globMatch.
+func globMatchFold(pattern, name string) (bool, error) {
+	return globMatch(strings.ToLower(pattern), strings.ToLower(name))
+}
@@ -0,0 +1,200 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_globMatch(t *testing.T) {
+	type args struct {
+		pattern string
+		name    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "literal match",
+			args: args{
+				pattern: "example.com",
+				name:    "example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "literal mismatch",
+			args: args{
+				pattern: "example.com",
+				name:    "example.org",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "star matches within segment",
+			args: args{
+				pattern: "*.example.com",
+				name:    "api.example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "star does not cross path separator",
+			args: args{
+				pattern: "/var/*.log",
+				name:    "/var/log/syslog.log",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "double star crosses path separator",
+			args: args{
+				pattern: "/var/**.log",
+				name:    "/var/log/syslog.log",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "double star segment matches zero segments",
+			args: args{
+				pattern: "/etc/**/hosts",
+				name:    "/etc/hosts",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "double star segment matches multiple segments",
+			args: args{
+				pattern: "/etc/**/hosts",
+				name:    "/etc/a/b/c/hosts",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "question mark matches single character",
+			args: args{
+				pattern: "node-?",
+				name:    "node-1",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "question mark does not match empty",
+			args: args{
+				pattern: "node-?",
+				name:    "node-",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "character class range",
+			args: args{
+				pattern: "host[a-c]",
+				name:    "hostb",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "negated character class",
+			args: args{
+				pattern: "host[!a-c]",
+				name:    "hostb",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "escaped star",
+			args: args{
+				pattern: `a\*b`,
+				name:    "a*b",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "unicode characters",
+			args: args{
+				pattern: "gr??e",
+				name:    "grüße",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "unterminated class",
+			args: args{
+				pattern: "host[a-c",
+				name:    "hosta",
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "trailing escape",
+			args: args{
+				pattern: `host\`,
+				name:    "host",
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := globMatch(tt.args.pattern, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("globMatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("globMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_globMatchFold(t *testing.T) {
+	got, err := globMatchFold("*.EXAMPLE.com", "Api.Example.COM")
+	if err != nil {
+		t.Errorf("globMatchFold() error = %v", err)
+		return
+	}
+	if !got {
+		t.Errorf("globMatchFold() = %v, want %v", got, true)
+	}
+}
+
+func Test_globMatch_pathological(t *testing.T) {
+	patterns := []string{
+		"*a*a*a*a*a*a*a*ab",
+		"**a**a**a**a**a**a**a**ab",
+		"**/a*/**/a*/**/a*/**/b",
+	}
+	name := strings.Repeat("a", 100)
+	path := strings.Repeat("a/", 50)
+	for _, pattern := range patterns {
+		start := time.Now()
+		for _, s := range []string{name, path} {
+			if got, err := globMatch(pattern, s); err != nil || got {
+				t.Errorf("globMatch(%q) = %v, %v, want false", pattern, got, err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("globMatch(%q) took %v", pattern, elapsed)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultHexDumpBytesPerLine defines the number of bytes per row used by default, like xxd.
+const DefaultHexDumpBytesPerLine = 16
+
+// HexDumpOptions configures the hex dump renderer.
+type HexDumpOptions struct {
+	// Color defines whether ANSI colors are used to mark differing bytes.
+	Color ColorMode
+	// BytesPerLine defines the number of bytes per row.
+	// If 0, DefaultHexDumpBytesPerLine is used.
+	BytesPerLine int
+	// Context defines the number of identical rows shown before and after differing rows.
+	// Longer identical regions are collapsed into a single "..." line, 0 collapses all identical rows.
+	Context int
+}
+
+// hexCell defines a pair of aligned bytes of the original and the new data.
+// A nil byte is a gap caused by an inserted or deleted byte.
+type hexCell struct {
+	old, new *byte
+	// oldOffset and newOffset define the positions of the cell in the original and new data.
+	oldOffset, newOffset int
+}
+
+// differs reports whether the bytes of the cell differ.
+func (c hexCell) differs() bool {
+	return c.old == nil || c.new == nil || *c.old != *c.new
+}
+
+// RenderHexDiff writes the hex dump of the difference between a and b to w, see RenderHexDump.
+func RenderHexDiff(w io.Writer, a, b []byte, opts HexDumpOptions) error {
+	return RenderHexDump(w, a, BytesDiff(a, b), opts)
+}
+
+// RenderHexDump writes an xxd style side-by-side hex dump of original and the data
+// the reports produce to w. Every row shows the offset, the hex columns and the ASCII
+// gutter of both sides. Inserted and deleted bytes are aligned with gaps ("--"),
+// differing bytes are marked with "*" or colored. Identical regions are collapsed
+// into "..." lines that state the number of identical bytes.
+func RenderHexDump(w io.Writer, original []byte, reports Reports, opts HexDumpOptions) error {
+	cells, err := hexCells(original, reports)
+	if err != nil {
+		return err
+	}
+	perLine := opts.BytesPerLine
+	if perLine <= 0 {
+		perLine = DefaultHexDumpBytesPerLine
+	}
+	context := opts.Context
+	if context < 0 {
+		context = 0
+	}
+	color := RenderOptions{Color: opts.Color}.useColor(w)
+
+	rows := [][]hexCell{}
+	for start := 0; start < len(cells); start += perLine {
+		end := start + perLine
+		if end > len(cells) {
+			end = len(cells)
+		}
+		rows = append(rows, cells[start:end])
+	}
+	// visible marks the rows that differ or are within context of a differing row
+	visible := make([]bool, len(rows))
+	for i, row := range rows {
+		if !hexRowDiffers(row) {
+			continue
+		}
+		for k := i - context; k <= i+context; k++ {
+			if k >= 0 && k < len(rows) {
+				visible[k] = true
+			}
+		}
+	}
+
+	out := strings.Builder{}
+	for i := 0; i < len(rows); {
+		if visible[i] {
+			writeHexRow(&out, rows[i], perLine, color)
+			i++
+			continue
+		}
+		collapsed := 0
+		for ; i < len(rows) && !visible[i]; i++ {
+			collapsed += len(rows[i])
+		}
+		fmt.Fprintf(&out, "... %d identical %s ...\n", collapsed, plural(collapsed, "byte", "bytes"))
+	}
+	_, err = io.WriteString(w, out.String())
+	return err
+}
+
+// hexCells aligns original and the data the reports produce.
+func hexCells(original []byte, reports Reports) ([]hexCell, error) {
+	cells := []hexCell{}
+	// i is the index in original, j the index in the new data of the next equal byte
+	i, j := 0, 0
+	equal := func(end int) {
+		for ; i < end; i, j = i+1, j+1 {
+			cells = append(cells, hexCell{old: &original[i], new: &original[i], oldOffset: i, newOffset: j})
+		}
+	}
+	err := reports.replay(original, func(report Report, ri, rj int) {
+		equal(ri)
+		new := report.New
+		switch report.Kind {
+		case ReportKindChanged:
+			cells = append(cells, hexCell{old: &original[ri], new: &new, oldOffset: ri, newOffset: rj})
+			i, j = ri+1, rj+1
+		case ReportKindDeleted:
+			cells = append(cells, hexCell{old: &original[ri], oldOffset: ri, newOffset: rj})
+			i = ri + 1
+		case ReportKindInserted:
+			cells = append(cells, hexCell{new: &new, oldOffset: ri, newOffset: rj})
+			j = rj + 1
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	equal(len(original))
+	return cells, nil
+}
+
+// hexRowDiffers reports whether a cell of the row differs.
+func hexRowDiffers(row []hexCell) bool {
+	for _, cell := range row {
+		if cell.differs() {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHexRow writes the original side and the new side of the row.
+func writeHexRow(out *strings.Builder, row []hexCell, perLine int, color bool) {
+	writeHexSide(out, row, perLine, color, func(c hexCell) (*byte, int) { return c.old, c.oldOffset }, ansiDeletedHighlight)
+	// align the new side if the last row is shorter
+	out.WriteString(strings.Repeat(" ", perLine-len(row)))
+	out.WriteString("  ")
+	writeHexSide(out, row, perLine, color, func(c hexCell) (*byte, int) { return c.new, c.newOffset }, ansiInsertedHighlight)
+	out.WriteString("\n")
+}
+
+// writeHexSide writes the offset, the hex columns and the ASCII gutter of one side of a row.
+func writeHexSide(out *strings.Builder, row []hexCell, perLine int, color bool, side func(hexCell) (*byte, int), highlight string) {
+	_, offset := side(row[0])
+	fmt.Fprintf(out, "%08x:", offset)
+	ascii := strings.Builder{}
+	for _, cell := range row {
+		b, _ := side(cell)
+		hex, char := "--", " "
+		if b != nil {
+			hex, char = fmt.Sprintf("%02x", *b), printableByte(*b)
+		}
+		switch {
+		case !cell.differs():
+			out.WriteString(" " + hex)
+			ascii.WriteString(char)
+		case color:
+			out.WriteString(" " + highlight + hex + ansiReset)
+			ascii.WriteString(highlight + char + ansiReset)
+		default:
+			out.WriteString("*" + hex)
+			ascii.WriteString(char)
+		}
+	}
+	out.WriteString(strings.Repeat("   ", perLine-len(row)))
+	out.WriteString("  " + ascii.String())
+}
+
+// printableByte returns b as a string if it is printable ASCII, otherwise ".".
+func printableByte(b byte) string {
+	if b >= 0x20 && b < 0x7f {
+		return string(rune(b))
+	}
+	return "."
+}
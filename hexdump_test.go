@@ -0,0 +1,90 @@
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRenderHexDiff(t *testing.T) {
+	type args struct {
+		a    []byte
+		b    []byte
+		opts HexDumpOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "equal",
+			args: args{
+				a:    []byte("abcdefgh"),
+				b:    []byte("abcdefgh"),
+				opts: HexDumpOptions{Color: ColorNever, BytesPerLine: 4},
+			},
+			want: "... 8 identical bytes ...\n",
+		},
+		{
+			name: "changed and deleted bytes",
+			args: args{
+				a:    []byte("Hello"),
+				b:    []byte("Help"),
+				opts: HexDumpOptions{Color: ColorNever, BytesPerLine: 8},
+			},
+			want: "00000000: 48 65 6c*6c*6f           Hello     00000000: 48 65 6c*70*--           Help \n",
+		},
+		{
+			name: "identical regions collapse around context",
+			args: args{
+				a:    []byte("0000111122223333444455556666"),
+				b:    []byte("000011112222x333444455556666"),
+				opts: HexDumpOptions{Color: ColorNever, BytesPerLine: 4, Context: 1},
+			},
+			want: "... 8 identical bytes ...\n" +
+				"00000008: 32 32 32 32  2222  00000008: 32 32 32 32  2222\n" +
+				"0000000c:*33 33 33 33  3333  0000000c:*78 33 33 33  x333\n" +
+				"00000010: 34 34 34 34  4444  00000010: 34 34 34 34  4444\n" +
+				"... 8 identical bytes ...\n",
+		},
+		{
+			name: "inserted bytes shift the offsets of the new data",
+			args: args{
+				a:    []byte{0x00, 0x01, 0x02, 0x03},
+				b:    []byte{0xff, 0x00, 0x01, 0x02, 0x03},
+				opts: HexDumpOptions{Color: ColorNever, BytesPerLine: 4},
+			},
+			want: "00000000:*-- 00 01 02   ...  00000000:*ff 00 01 02  ....\n" +
+				"... 1 identical byte ...\n",
+		},
+		{
+			name: "colored",
+			args: args{
+				a:    []byte("a"),
+				b:    []byte("b"),
+				opts: HexDumpOptions{Color: ColorAlways, BytesPerLine: 1},
+			},
+			want: "00000000: \x1b[1;37;41m61\x1b[0m  \x1b[1;37;41ma\x1b[0m  00000000: \x1b[1;37;42m62\x1b[0m  \x1b[1;37;42mb\x1b[0m\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			if err := RenderHexDiff(&buf, tt.args.a, tt.args.b, tt.args.opts); err != nil {
+				t.Fatalf("RenderHexDiff() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("RenderHexDiff() = \n%q\n, want \n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHexDump(t *testing.T) {
+	x := byte('x')
+	err := RenderHexDump(&bytes.Buffer{}, []byte("a"), Reports{{Kind: ReportKindChanged, Index: 0, Original: &x, New: 'y'}}, HexDumpOptions{})
+	if !errors.Is(err, ErrInconsistentReports) {
+		t.Errorf("RenderHexDump() error = %v, wantErr %v", err, ErrInconsistentReports)
+	}
+}
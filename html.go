@@ -0,0 +1,360 @@
+package compare
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HTMLOptions configures the HTML report.
+type HTMLOptions struct {
+	// Title defines the title of the report.
+	Title string
+	// Context defines the number of unchanged rows shown around changes.
+	// Longer unchanged regions are collapsed, 0 collapses all unchanged rows like a negative value.
+	// Use DefaultContextLines for the default of diff and patch.
+	Context int
+}
+
+// HTMLSection defines a diff result that is part of an HTML report.
+// Sections are created by TextSection, BytesSection and ValueSection.
+type HTMLSection struct {
+	name string
+	kind string
+	// stats summarizes the section, similarity is only shown if it is known
+	stats         Stats
+	hasSimilarity bool
+	rows          []htmlRow
+	// collapsible reports whether unchanged rows are collapsed
+	collapsible bool
+}
+
+// htmlRow defines a row of the side-by-side and the unified view.
+// HasOld and HasNew are false if the row does not exist on that side.
+type htmlRow struct {
+	Kind     string
+	OldLabel string
+	NewLabel string
+	HasOld   bool
+	HasNew   bool
+	Old      []htmlSpan
+	New      []htmlSpan
+}
+
+// htmlSpan defines a run of text with a CSS class.
+type htmlSpan struct {
+	Class string
+	Text  string
+}
+
+// htmlGroup defines consecutive rows that are either shown or collapsed.
+type htmlGroup struct {
+	Collapsed bool
+	Rows      []htmlRow
+}
+
+// TextSection returns a section with the line diff of a and b.
+// Changed lines are highlighted character by character.
+func TextSection(name, a, b string) HTMLSection {
+	section := HTMLSection{name: name, kind: "text", hasSimilarity: true, collapsible: true}
+	oldLine, newLine := 0, 0
+	for _, line := range DiffInline(a, b) {
+		row := htmlRow{Kind: line.Kind.String()}
+		switch line.Kind {
+		case ReportKindEqual:
+			section.stats.Unchanged++
+		case ReportKindChanged:
+			section.stats.Changed++
+		case ReportKindDeleted:
+			section.stats.Deleted++
+		case ReportKindInserted:
+			section.stats.Inserted++
+		}
+		if line.Kind != ReportKindInserted {
+			oldLine++
+			row.OldLabel = strconv.Itoa(oldLine)
+			row.HasOld, row.Old = true, htmlSegments(line.Old, line.Kind == ReportKindChanged)
+		}
+		if line.Kind != ReportKindDeleted {
+			newLine++
+			row.NewLabel = strconv.Itoa(newLine)
+			row.HasNew, row.New = true, htmlSegments(line.New, line.Kind == ReportKindChanged)
+		}
+		section.rows = append(section.rows, row)
+	}
+	section.stats.OldLength, section.stats.NewLength = oldLine, newLine
+	return section
+}
+
+// BytesSection returns a section with the hex dump of the difference between a and b.
+func BytesSection(name string, a, b []byte) HTMLSection {
+	section := HTMLSection{name: name, kind: "bytes", stats: BytesStats(a, b), hasSimilarity: true, collapsible: true}
+	// the reports of BytesDiff are always consistent with a
+	cells, _ := hexCells(a, BytesDiff(a, b))
+	for start := 0; start < len(cells); start += DefaultHexDumpBytesPerLine {
+		end := start + DefaultHexDumpBytesPerLine
+		if end > len(cells) {
+			end = len(cells)
+		}
+		row := cells[start:end]
+		kind := ReportKindEqual
+		if hexRowDiffers(row) {
+			kind = ReportKindChanged
+		}
+		section.rows = append(section.rows, htmlRow{
+			Kind:     kind.String(),
+			OldLabel: fmt.Sprintf("%08x", row[0].oldOffset),
+			NewLabel: fmt.Sprintf("%08x", row[0].newOffset),
+			HasOld:   true,
+			HasNew:   true,
+			Old:      htmlHexSide(row, func(c hexCell) *byte { return c.old }, "hl-del"),
+			New:      htmlHexSide(row, func(c hexCell) *byte { return c.new }, "hl-ins"),
+		})
+	}
+	return section
+}
+
+// ValueSection returns a section with the structural difference between a and b, see DiffValues.
+func ValueSection(name string, a, b interface{}) HTMLSection {
+	section := HTMLSection{name: name, kind: "values"}
+	for _, report := range DiffValues(a, b) {
+		row := htmlRow{
+			Kind:     report.Kind.String(),
+			OldLabel: report.Path,
+			NewLabel: report.Path,
+			HasOld:   report.Kind != ReportKindInserted,
+			HasNew:   report.Kind != ReportKindDeleted,
+		}
+		switch report.Kind {
+		case ReportKindChanged:
+			section.stats.Changed++
+			row.Old = []htmlSpan{{Class: "hl-del", Text: fmt.Sprint(report.Old)}}
+			row.New = []htmlSpan{{Class: "hl-ins", Text: fmt.Sprint(report.New)}}
+		case ReportKindDeleted:
+			section.stats.Deleted++
+			row.Old = []htmlSpan{{Text: fmt.Sprint(report.Old)}}
+		case ReportKindInserted:
+			section.stats.Inserted++
+			row.New = []htmlSpan{{Text: fmt.Sprint(report.New)}}
+		}
+		section.rows = append(section.rows, row)
+	}
+	return section
+}
+
+// htmlSegments converts the segments of a line into spans. Changes are only highlighted in changed lines.
+func htmlSegments(segments Segments, highlight bool) []htmlSpan {
+	spans := []htmlSpan{}
+	for _, segment := range segments {
+		span := htmlSpan{Text: segment.Text}
+		if highlight {
+			switch segment.Kind {
+			case ReportKindDeleted:
+				span.Class = "hl-del"
+			case ReportKindInserted:
+				span.Class = "hl-ins"
+			}
+			// changed line terminators are the last segment of a line
+			if segment.Kind != ReportKindEqual && strings.TrimRight(segment.Text, "\r\n") == "" {
+				span.Text = visibleWhitespace(segment.Text)
+			}
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// htmlHexSide converts one side of a hex dump row into spans: the hex columns followed by the ASCII gutter.
+func htmlHexSide(row []hexCell, side func(hexCell) *byte, highlight string) []htmlSpan {
+	hex, ascii := []htmlSpan{}, []htmlSpan{}
+	for _, cell := range row {
+		h, a := "--", " "
+		if b := side(cell); b != nil {
+			h, a = fmt.Sprintf("%02x", *b), printableByte(*b)
+		}
+		class := ""
+		if cell.differs() {
+			class = highlight
+		}
+		hex = appendSpan(appendSpan(hex, class, h), "", " ")
+		ascii = appendSpan(ascii, class, a)
+	}
+	hex = appendSpan(hex, "", strings.Repeat("   ", DefaultHexDumpBytesPerLine-len(row))+" ")
+	for _, span := range ascii {
+		hex = appendSpan(hex, span.Class, span.Text)
+	}
+	return hex
+}
+
+// appendSpan appends the text to the last span if it has the same class.
+func appendSpan(spans []htmlSpan, class, text string) []htmlSpan {
+	if last := len(spans) - 1; last >= 0 && spans[last].Class == class {
+		spans[last].Text += text
+		return spans
+	}
+	return append(spans, htmlSpan{Class: class, Text: text})
+}
+
+// groups splits the rows into shown and collapsed groups. Unchanged rows further than
+// context rows away from a change are collapsed.
+func (s HTMLSection) groups(context int) []htmlGroup {
+	groups := []htmlGroup{}
+	add := func(collapsed bool, rows []htmlRow) {
+		if len(rows) == 0 {
+			return
+		}
+		if last := len(groups) - 1; last >= 0 && groups[last].Collapsed == collapsed {
+			groups[last].Rows = append(groups[last].Rows, rows...)
+			return
+		}
+		groups = append(groups, htmlGroup{Collapsed: collapsed, Rows: append([]htmlRow{}, rows...)})
+	}
+	for start := 0; start < len(s.rows); {
+		end := start
+		for end < len(s.rows) && s.rows[end].Kind == ReportKindEqual.String() {
+			end++
+		}
+		if end == start {
+			add(false, s.rows[start:start+1])
+			start++
+			continue
+		}
+		// start:end is a run of unchanged rows
+		head, tail := context, context
+		if start == 0 {
+			head = 0
+		}
+		if end == len(s.rows) {
+			tail = 0
+		}
+		if !s.collapsible || end-start <= head+tail {
+			add(false, s.rows[start:end])
+		} else {
+			add(false, s.rows[start:start+head])
+			add(true, s.rows[start+head:end-tail])
+			add(false, s.rows[end-tail:end])
+		}
+		start = end
+	}
+	return groups
+}
+
+// htmlTemplate renders the report. The views are switched with radio buttons, so that
+// the report works without scripts and external assets.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI",Helvetica,Arial,sans-serif;margin:2em;color:#1f2328}
+table.summary{border-collapse:collapse;margin-bottom:1.5em}
+table.summary td,table.summary th{border:1px solid #d0d7de;padding:.3em .8em;text-align:right}
+table.summary td:first-child,table.summary th:first-child{text-align:left}
+.ins-count{color:#1a7f37}.del-count{color:#cf222e}
+input[name=view]{margin-left:1em}
+#view-split:checked~main .unified,#view-unified:checked~main .split{display:none}
+section{margin:1.5em 0;border:1px solid #d0d7de;border-radius:6px}
+section h2{font-size:1em;margin:0;padding:.5em .8em;background:#f6f8fa;border-bottom:1px solid #d0d7de}
+.diff{font-family:ui-monospace,SFMono-Regular,Menlo,Consolas,monospace;font-size:12px}
+.row{display:grid;white-space:pre-wrap;word-break:break-all}
+.split .row{grid-template-columns:6em 1fr 6em 1fr}
+.unified .row{grid-template-columns:6em 6em 1.5em 1fr}
+.num{color:#6e7781;text-align:right;padding-right:.8em;user-select:none}
+.del,.split .changed .old{background:#ffebe9}
+.ins,.split .changed .new{background:#dafbe1}
+.split .deleted .old{background:#ffebe9}.split .inserted .new{background:#dafbe1}
+.hl-del{background:#ff8182}.hl-ins{background:#4ac26b}
+.empty{background:#f6f8fa}
+details summary{cursor:pointer;color:#0969da;background:#ddf4ff;padding:.2em .8em}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="summary">
+<tr><th>Section</th><th>Type</th><th>Inserted</th><th>Deleted</th><th>Changed</th><th>Similarity</th></tr>
+{{- range .Sections}}
+<tr><td><a href="#{{.ID}}">{{.Name}}</a></td><td>{{.Kind}}</td><td class="ins-count">+{{.Stats.Inserted}}</td><td class="del-count">-{{.Stats.Deleted}}</td><td>~{{.Stats.Changed}}</td><td>{{.Similarity}}</td></tr>
+{{- end}}
+</table>
+View:
+<input type="radio" name="view" id="view-split" checked><label for="view-split">Side by side</label>
+<input type="radio" name="view" id="view-unified"><label for="view-unified">Unified</label>
+<main>
+{{- range .Sections}}
+<section id="{{.ID}}">
+<h2>{{.Name}} ({{.Kind}})</h2>
+<div class="diff split">
+{{- range .Groups}}{{if .Collapsed}}<details><summary>{{len .Rows}} unchanged rows</summary>{{end}}
+{{- range .Rows}}
+<div class="row {{.Kind}}"><span class="num">{{.OldLabel}}</span><span class="old{{if not .HasOld}} empty{{end}}">{{template "spans" .Old}}</span><span class="num">{{.NewLabel}}</span><span class="new{{if not .HasNew}} empty{{end}}">{{template "spans" .New}}</span></div>
+{{- end}}
+{{if .Collapsed}}</details>{{end}}{{end}}
+</div>
+<div class="diff unified">
+{{- range .Groups}}{{if .Collapsed}}<details><summary>{{len .Rows}} unchanged rows</summary>{{end}}
+{{- range .Rows}}
+{{- if eq .Kind "equal"}}
+<div class="row"><span class="num">{{.OldLabel}}</span><span class="num">{{.NewLabel}}</span><span> </span><span>{{template "spans" .Old}}</span></div>
+{{- else}}
+{{- if .HasOld}}
+<div class="row del"><span class="num">{{.OldLabel}}</span><span class="num"></span><span>-</span><span>{{template "spans" .Old}}</span></div>
+{{- end}}
+{{- if .HasNew}}
+<div class="row ins"><span class="num"></span><span class="num">{{.NewLabel}}</span><span>+</span><span>{{template "spans" .New}}</span></div>
+{{- end}}
+{{- end}}
+{{- end}}
+{{if .Collapsed}}</details>{{end}}{{end}}
+</div>
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "spans"}}{{range .}}{{if .Class}}<span class="{{.Class}}">{{.Text}}</span>{{else}}{{.Text}}{{end}}{{end}}{{end}}`))
+
+// htmlSectionData defines the template data of a section.
+type htmlSectionData struct {
+	ID         string
+	Name       string
+	Kind       string
+	Stats      Stats
+	Similarity string
+	Groups     []htmlGroup
+}
+
+// RenderHTML writes a self-contained HTML report of the sections to w. The report starts with
+// a summary of all sections, followed by the sections in a side-by-side and a unified view.
+// Unchanged regions are collapsed into expandable <details> elements.
+func RenderHTML(w io.Writer, opts HTMLOptions, sections ...HTMLSection) error {
+	context := opts.Context
+	if context < 0 {
+		context = 0
+	}
+	title := opts.Title
+	if title == "" {
+		title = "Diff report"
+	}
+	data := struct {
+		Title    string
+		Sections []htmlSectionData
+	}{Title: title}
+	for i, section := range sections {
+		similarity := "n/a"
+		if section.hasSimilarity {
+			similarity = fmt.Sprintf("%.1f%%", section.stats.Similarity()*100)
+		}
+		data.Sections = append(data.Sections, htmlSectionData{
+			ID:         "section-" + strconv.Itoa(i+1),
+			Name:       section.name,
+			Kind:       section.kind,
+			Stats:      section.stats,
+			Similarity: similarity,
+			Groups:     section.groups(context),
+		})
+	}
+	return htmlTemplate.Execute(w, data)
+}
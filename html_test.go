@@ -0,0 +1,119 @@
+package compare
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n<b>old</b>\n"
+	b := "1\n2\n3\n4\n5\n6\n7\n8\n<b>new</b>\nadded\n"
+	buf := bytes.Buffer{}
+	err := RenderHTML(&buf, HTMLOptions{Title: "Release <1.2>", Context: 2},
+		TextSection("config.txt", a, b),
+		BytesSection("frame.bin", []byte("Hello"), []byte("Help")),
+		ValueSection("settings", map[string]int{"a": 1, "b": 2}, map[string]int{"a": 2}),
+	)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"<title>Release &lt;1.2&gt;</title>",
+		`<td><a href="#section-1">config.txt</a></td><td>text</td><td class="ins-count">+1</td><td class="del-count">-0</td><td>~1</td><td>84.2%</td>`,
+		`<td><a href="#section-2">frame.bin</a></td><td>bytes</td><td class="ins-count">+0</td><td class="del-count">-1</td><td>~1</td><td>66.7%</td>`,
+		`<td><a href="#section-3">settings</a></td><td>values</td><td class="ins-count">+0</td><td class="del-count">-1</td><td>~1</td><td>n/a</td>`,
+		"<details><summary>6 unchanged rows</summary>",
+		`<span class="new">&lt;b&gt;<span class="hl-ins">new</span>&lt;/b&gt;</span>`,
+		`<div class="row ins"><span class="num"></span><span class="num">10</span><span>+</span><span>added</span></div>`,
+		`<span class="old">48 65 6c <span class="hl-del">6c</span> <span class="hl-del">6f</span>`,
+		`<div class="row deleted"><span class="num">b</span><span class="old">2</span><span class="num">b</span><span class="new empty"></span></div>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderHTML() does not contain %q", want)
+		}
+	}
+	for _, external := range []string{"<script", "<link", "src=", "http://", "https://"} {
+		if strings.Contains(got, external) {
+			t.Errorf("RenderHTML() references external assets: %q", external)
+		}
+	}
+}
+
+func TestRenderHTML_zeroContext(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := RenderHTML(&buf, HTMLOptions{}, TextSection("config.txt", "1\n2\n3\n4\nold\n", "1\n2\n3\n4\nnew\n")); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "<details><summary>4 unchanged rows</summary>") {
+		t.Errorf("RenderHTML() with zero context does not collapse all unchanged rows:\n%s", got)
+	}
+}
+
+func TestHTMLSection_groups(t *testing.T) {
+	rows := func(kinds ...ReportKind) []htmlRow {
+		out := []htmlRow{}
+		for _, kind := range kinds {
+			out = append(out, htmlRow{Kind: kind.String()})
+		}
+		return out
+	}
+	e, c := ReportKindEqual, ReportKindChanged
+	tests := []struct {
+		name    string
+		section HTMLSection
+		context int
+		want    []htmlGroup
+	}{
+		{
+			name:    "unchanged regions are collapsed around changes",
+			section: HTMLSection{rows: rows(e, e, e, c, e, e, e, e, c, e, e), collapsible: true},
+			context: 1,
+			want: []htmlGroup{
+				{Collapsed: true, Rows: rows(e, e)},
+				{Collapsed: false, Rows: rows(e, c, e)},
+				{Collapsed: true, Rows: rows(e, e)},
+				{Collapsed: false, Rows: rows(e, c, e)},
+				{Collapsed: true, Rows: rows(e)},
+			},
+		},
+		{
+			name:    "short unchanged regions are kept",
+			section: HTMLSection{rows: rows(c, e, e, c), collapsible: true},
+			context: 1,
+			want: []htmlGroup{
+				{Collapsed: false, Rows: rows(c, e, e, c)},
+			},
+		},
+		{
+			name:    "not collapsible",
+			section: HTMLSection{rows: rows(e, e, e, c), collapsible: false},
+			context: 0,
+			want: []htmlGroup{
+				{Collapsed: false, Rows: rows(e, e, e, c)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.section.groups(tt.context); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTMLSection.groups() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSection_lineTerminators(t *testing.T) {
+	section := TextSection("crlf.txt", "a\r\n", "a\n")
+	if len(section.rows) != 1 {
+		t.Fatalf("TextSection() rows = %+v, want 1 row", section.rows)
+	}
+	row := section.rows[0]
+	wantOld := []htmlSpan{{Text: "a"}, {Text: "␍␊", Class: "hl-del"}}
+	wantNew := []htmlSpan{{Text: "a"}, {Text: "␊", Class: "hl-ins"}}
+	if row.Kind != "changed" || !reflect.DeepEqual(row.Old, wantOld) || !reflect.DeepEqual(row.New, wantNew) {
+		t.Errorf("TextSection() row = %+v, want changed row with old %+v and new %+v", row, wantOld, wantNew)
+	}
+}
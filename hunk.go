@@ -0,0 +1,103 @@
+package compare
+
+// Hunks defines a list of hunks.
+type Hunks []Hunk
+
+// Hunk defines a contiguous range of changes between the original and the new data.
+// The ranges include the surrounding context.
+type Hunk struct {
+	// OldStart defines the offset of the first byte of the hunk in the original data.
+	OldStart int
+	// OldEnd defines the offset after the last byte of the hunk in the original data.
+	OldEnd int
+	// NewStart defines the offset of the first byte of the hunk in the new data.
+	NewStart int
+	// NewEnd defines the offset after the last byte of the hunk in the new data.
+	NewEnd int
+	// Old defines the bytes of the hunk in the original data.
+	Old []byte
+	// New defines the bytes of the hunk in the new data.
+	New []byte
+	// Changed defines the number of changed bytes.
+	Changed int
+	// Inserted defines the number of inserted bytes.
+	Inserted int
+	// Deleted defines the number of deleted bytes.
+	Deleted int
+}
+
+// BytesHunks returns the hunks of the difference between a and b with context bytes
+// of surrounding equal data. a is the original and b the new data.
+func BytesHunks(a, b []byte, context int) Hunks {
+	// the reports of BytesDiff are always consistent with a
+	hunks, _ := BytesDiff(a, b).Hunks(a, context)
+	return hunks
+}
+
+// Hunks groups the reports into hunks. Reports that are separated by at most
+// 2*context equal bytes share a hunk, each hunk is surrounded by up to context equal bytes.
+// original is the data the reports were created for, ErrInconsistentReports is returned
+// if the reports do not match it.
+func (r Reports) Hunks(original []byte, context int) (Hunks, error) {
+	if context < 0 {
+		context = 0
+	}
+	hunks := Hunks{}
+	var hunk *Hunk
+	// end is the index in original and newEnd the index in the new data after the last report
+	end, newEnd := 0, 0
+	err := r.replay(original, func(report Report, i, j int) {
+		if hunk != nil && i-end > 2*context {
+			hunk.close(original, end, newEnd, context)
+			hunks = append(hunks, *hunk)
+			hunk = nil
+		}
+		if hunk == nil {
+			start := i - context
+			if start < 0 {
+				start = 0
+			}
+			hunk = &Hunk{
+				OldStart: start,
+				NewStart: j - (i - start),
+				New:      append([]byte{}, original[start:i]...),
+			}
+		} else {
+			hunk.New = append(hunk.New, original[end:i]...)
+		}
+		end, newEnd = i, j
+		switch report.Kind {
+		case ReportKindChanged:
+			hunk.New = append(hunk.New, report.New)
+			hunk.Changed++
+			end, newEnd = i+1, j+1
+		case ReportKindDeleted:
+			hunk.Deleted++
+			end = i + 1
+		case ReportKindInserted:
+			hunk.New = append(hunk.New, report.New)
+			hunk.Inserted++
+			newEnd = j + 1
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hunk != nil {
+		hunk.close(original, end, newEnd, context)
+		hunks = append(hunks, *hunk)
+	}
+	return hunks, nil
+}
+
+// close adds the trailing context after the last report of the hunk located at end in
+// original and at newEnd in the new data.
+func (h *Hunk) close(original []byte, end, newEnd, context int) {
+	h.OldEnd = end + context
+	if h.OldEnd > len(original) {
+		h.OldEnd = len(original)
+	}
+	h.NewEnd = newEnd + (h.OldEnd - end)
+	h.Old = append([]byte{}, original[h.OldStart:h.OldEnd]...)
+	h.New = append(h.New, original[end:h.OldEnd]...)
+}
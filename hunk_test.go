@@ -0,0 +1,145 @@
+package compare
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBytesHunks(t *testing.T) {
+	type args struct {
+		a       []byte
+		b       []byte
+		context int
+	}
+	tests := []struct {
+		name string
+		args args
+		want Hunks
+	}{
+		{
+			name: "equal",
+			args: args{
+				a:       []byte("abc"),
+				b:       []byte("abc"),
+				context: 2,
+			},
+			want: Hunks{},
+		},
+		{
+			name: "single hunk with context",
+			args: args{
+				a:       []byte("0123456789"),
+				b:       []byte("01234xx6789"),
+				context: 2,
+			},
+			want: Hunks{
+				{
+					OldStart: 3, OldEnd: 8,
+					NewStart: 3, NewEnd: 9,
+					Old:     []byte("34567"),
+					New:     []byte("34xx67"),
+					Changed: 1, Inserted: 1, Deleted: 0,
+				},
+			},
+		},
+		{
+			name: "distant changes produce separate hunks",
+			args: args{
+				a:       []byte("a123456789b"),
+				b:       []byte("123456789"),
+				context: 1,
+			},
+			want: Hunks{
+				{
+					OldStart: 0, OldEnd: 2,
+					NewStart: 0, NewEnd: 1,
+					Old:     []byte("a1"),
+					New:     []byte("1"),
+					Changed: 0, Inserted: 0, Deleted: 1,
+				},
+				{
+					OldStart: 9, OldEnd: 11,
+					NewStart: 8, NewEnd: 9,
+					Old:     []byte("9b"),
+					New:     []byte("9"),
+					Changed: 0, Inserted: 0, Deleted: 1,
+				},
+			},
+		},
+		{
+			name: "close changes share a hunk",
+			args: args{
+				a:       []byte("a12b"),
+				b:       []byte("A12B"),
+				context: 1,
+			},
+			want: Hunks{
+				{
+					OldStart: 0, OldEnd: 4,
+					NewStart: 0, NewEnd: 4,
+					Old:     []byte("a12b"),
+					New:     []byte("A12B"),
+					Changed: 2, Inserted: 0, Deleted: 0,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BytesHunks(tt.args.a, tt.args.b, tt.args.context); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BytesHunks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReports_Hunks(t *testing.T) {
+	x := byte('x')
+	_, err := Reports{{Kind: ReportKindChanged, Index: 0, Original: &x, New: 'y'}}.Hunks([]byte("a"), 3)
+	if !errors.Is(err, ErrInconsistentReports) {
+		t.Errorf("Reports.Hunks() error = %v, wantErr %v", err, ErrInconsistentReports)
+	}
+
+	// the hunks must cover the complete difference
+	rnd := rand.New(rand.NewSource(1))
+	random := func() []byte {
+		b := make([]byte, rnd.Intn(40))
+		for i := range b {
+			b[i] = byte('a' + rnd.Intn(3))
+		}
+		return b
+	}
+	for n := 0; n < 300; n++ {
+		a, b := random(), random()
+		context := rnd.Intn(4)
+		hunks, err := BytesDiff(a, b).Hunks(a, context)
+		if err != nil {
+			t.Fatalf("Reports.Hunks() error = %v", err)
+		}
+		got := []byte{}
+		oldEnd := 0
+		for _, hunk := range hunks {
+			if !reflect.DeepEqual(hunk.Old, a[hunk.OldStart:hunk.OldEnd]) || string(hunk.New) != string(b[hunk.NewStart:hunk.NewEnd]) {
+				t.Fatalf("Reports.Hunks(%q, %q) hunk %+v does not match the data", a, b, hunk)
+			}
+			got = append(got, a[oldEnd:hunk.OldStart]...)
+			got = append(got, hunk.New...)
+			oldEnd = hunk.OldEnd
+		}
+		got = append(got, a[oldEnd:]...)
+		if string(got) != string(b) {
+			t.Fatalf("Reports.Hunks(%q, %q) reconstructs %q", a, b, got)
+		}
+	}
+}
+
+func TestReports_Hunks_copiesData(t *testing.T) {
+	a := []byte("abc")
+	hunks := BytesHunks(a, []byte("axc"), 1)
+	a[0], a[1] = 'z', 'z'
+	if len(hunks) != 1 || string(hunks[0].Old) != "abc" || string(hunks[0].New) != "axc" {
+		t.Errorf("BytesHunks() = %+v, hunks must not share memory with the original data", hunks)
+	}
+}
@@ -0,0 +1,276 @@
+package compare
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Segment defines a run of text that is equal, deleted or inserted.
+type Segment struct {
+	// Kind is one of ReportKindEqual, ReportKindDeleted or ReportKindInserted.
+	Kind ReportKind `json:"kind"`
+	// Text defines the content of the segment.
+	Text string `json:"text"`
+}
+
+// Segments defines an ordered list of segments that transforms A into B.
+// The equal and deleted segments form A, the equal and inserted segments form B.
+type Segments []Segment
+
+// DiffWords returns the difference between a and b word by word.
+// Words are runs of letters, digits and underscores, runs of whitespace and single punctuation characters.
+func DiffWords(a, b string) Segments {
+	return diffTokens(splitWords(a), splitWords(b))
+}
+
+// DiffChars returns the difference between a and b character by character.
+// Characters are grapheme-like clusters as described by TextReport.
+func DiffChars(a, b string) Segments {
+	aChars, _ := splitCharacters(a)
+	bChars, _ := splitCharacters(b)
+	return diffTokens(aChars, bChars)
+}
+
+// diffTokens returns the segments of the shortest edit script of the tokens.
+func diffTokens(a, b []string) Segments {
+	script := DiffSequence(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+	segments := Segments{}
+	for _, edit := range script {
+		switch edit.Op {
+		case EditEqual:
+			segments = append(segments, Segment{Kind: ReportKindEqual, Text: strings.Join(a[edit.AStart:edit.AEnd], "")})
+		case EditDelete:
+			segments = append(segments, Segment{Kind: ReportKindDeleted, Text: strings.Join(a[edit.AStart:edit.AEnd], "")})
+		case EditInsert:
+			segments = append(segments, Segment{Kind: ReportKindInserted, Text: strings.Join(b[edit.BStart:edit.BEnd], "")})
+		}
+	}
+	return segments
+}
+
+// splitWords splits s into words, whitespace runs and single characters.
+func splitWords(s string) []string {
+	words := []string{}
+	for len(s) > 0 {
+		r, _ := utf8.DecodeRuneInString(s)
+		n := 0
+		switch {
+		case isWordRune(r):
+			n = strings.IndexFunc(s, func(r rune) bool { return !isWordRune(r) })
+		case unicode.IsSpace(r):
+			n = strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+		default:
+			n = clusterLength(s)
+		}
+		if n < 0 {
+			n = len(s)
+		}
+		words = append(words, s[:n])
+		s = s[n:]
+	}
+	return words
+}
+
+// isWordRune reports whether r is part of a word.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// Old returns the text of A.
+func (s Segments) Old() string {
+	sb := strings.Builder{}
+	for _, segment := range s {
+		if segment.Kind != ReportKindInserted {
+			sb.WriteString(segment.Text)
+		}
+	}
+	return sb.String()
+}
+
+// New returns the text of B.
+func (s Segments) New() string {
+	sb := strings.Builder{}
+	for _, segment := range s {
+		if segment.Kind != ReportKindDeleted {
+			sb.WriteString(segment.Text)
+		}
+	}
+	return sb.String()
+}
+
+// CleanupSemantic returns the segments with trivial equalities eliminated.
+// An equality is trivial if it is not longer than the deletions and insertions on
+// both of its sides, e.g. the single "e" in "mouse" → "sofa" ("mous[-e-]" vs "sof[+a+]").
+// Eliminated equalities become part of the surrounding deletion and insertion,
+// which makes the output easier to read at the cost of a longer edit script.
+func (s Segments) CleanupSemantic() Segments {
+	segments := s.merge()
+	for {
+		eliminated := false
+		for i := 1; i < len(segments)-1; i++ {
+			if segments[i].Kind != ReportKindEqual {
+				continue
+			}
+			before := changeLength(segments[:i], -1)
+			after := changeLength(segments[i+1:], 1)
+			length := utf8.RuneCountInString(segments[i].Text)
+			if before == 0 || after == 0 || length > before || length > after {
+				continue
+			}
+			text := segments[i].Text
+			replaced := append(Segments{}, segments[:i]...)
+			replaced = append(replaced, Segment{Kind: ReportKindDeleted, Text: text}, Segment{Kind: ReportKindInserted, Text: text})
+			replaced = append(replaced, segments[i+1:]...)
+			segments = replaced.merge()
+			eliminated = true
+			break
+		}
+		if !eliminated {
+			return segments
+		}
+	}
+}
+
+// changeLength returns the larger of the deleted and inserted rune counts of the
+// changes adjacent to an equality. dir -1 walks backwards, 1 walks forwards.
+func changeLength(segments Segments, dir int) int {
+	deleted, inserted := 0, 0
+	i := 0
+	if dir < 0 {
+		i = len(segments) - 1
+	}
+	for ; i >= 0 && i < len(segments) && segments[i].Kind != ReportKindEqual; i += dir {
+		if segments[i].Kind == ReportKindDeleted {
+			deleted += utf8.RuneCountInString(segments[i].Text)
+		} else {
+			inserted += utf8.RuneCountInString(segments[i].Text)
+		}
+	}
+	if deleted > inserted {
+		return deleted
+	}
+	return inserted
+}
+
+// merge joins adjacent equalities and merges all changes between two equalities
+// into a single deletion followed by a single insertion. Empty segments are dropped.
+func (s Segments) merge() Segments {
+	merged := Segments{}
+	deleted, inserted := strings.Builder{}, strings.Builder{}
+	flush := func() {
+		if deleted.Len() > 0 {
+			merged = append(merged, Segment{Kind: ReportKindDeleted, Text: deleted.String()})
+		}
+		if inserted.Len() > 0 {
+			merged = append(merged, Segment{Kind: ReportKindInserted, Text: inserted.String()})
+		}
+		deleted.Reset()
+		inserted.Reset()
+	}
+	for _, segment := range s {
+		switch segment.Kind {
+		case ReportKindDeleted:
+			deleted.WriteString(segment.Text)
+		case ReportKindInserted:
+			inserted.WriteString(segment.Text)
+		default:
+			flush()
+			if segment.Text == "" {
+				continue
+			}
+			if last := len(merged) - 1; last >= 0 && merged[last].Kind == ReportKindEqual {
+				merged[last].Text += segment.Text
+				continue
+			}
+			merged = append(merged, Segment{Kind: ReportKindEqual, Text: segment.Text})
+		}
+	}
+	flush()
+	return merged
+}
+
+// InlineLine defines a line of a split view.
+type InlineLine struct {
+	// Kind defines whether the line is equal, changed, deleted or inserted.
+	Kind ReportKind
+	// Old defines the equal and deleted segments of the line in A.
+	// Old is empty for inserted lines.
+	Old Segments
+	// New defines the equal and inserted segments of the line in B.
+	// New is empty for deleted lines.
+	New Segments
+}
+
+// DiffInline returns the line diff of a and b for a split view.
+// Deleted lines that are directly followed by inserted lines are paired as changed
+// lines and highlighted character by character after semantic cleanup.
+// Lines are compared including their terminators, so a line that only changes from "\r\n"
+// to "\n" is a changed line. Line terminators are not part of the segments, unless the
+// terminators of a changed line differ: then they are added as last deleted and inserted segments.
+func DiffInline(a, b string) []InlineLine {
+	aFull, bFull := SplitLines(a), SplitLines(b)
+	aLines, bLines := splitLinesTrimmed(aFull), splitLinesTrimmed(bFull)
+	script := DiffSequence(len(aFull), len(bFull), func(i, j int) bool {
+		return aFull[i] == bFull[j]
+	})
+	lines := []InlineLine{}
+	// aPos is the first line of A that has not been added to lines yet
+	aPos := 0
+	script.walkReports(func(kind ReportKind, i, j int) {
+		for ; aPos < i; aPos++ {
+			line := Segments{{Kind: ReportKindEqual, Text: aLines[aPos]}}
+			lines = append(lines, InlineLine{Kind: ReportKindEqual, Old: line, New: line})
+		}
+		switch kind {
+		case ReportKindChanged:
+			segments := DiffChars(aLines[i], bLines[j]).CleanupSemantic()
+			line := InlineLine{Kind: ReportKindChanged, Old: segments.filter(ReportKindInserted), New: segments.filter(ReportKindDeleted)}
+			if aTerm, bTerm := aFull[i][len(aLines[i]):], bFull[j][len(bLines[j]):]; aTerm != bTerm {
+				if aTerm != "" {
+					line.Old = append(line.Old, Segment{Kind: ReportKindDeleted, Text: aTerm})
+				}
+				if bTerm != "" {
+					line.New = append(line.New, Segment{Kind: ReportKindInserted, Text: bTerm})
+				}
+			}
+			lines = append(lines, line)
+			aPos = i + 1
+		case ReportKindDeleted:
+			lines = append(lines, InlineLine{Kind: ReportKindDeleted, Old: Segments{{Kind: ReportKindDeleted, Text: aLines[i]}}})
+			aPos = i + 1
+		case ReportKindInserted:
+			lines = append(lines, InlineLine{Kind: ReportKindInserted, New: Segments{{Kind: ReportKindInserted, Text: bLines[j]}}})
+		}
+	})
+	for ; aPos < len(aLines); aPos++ {
+		line := Segments{{Kind: ReportKindEqual, Text: aLines[aPos]}}
+		lines = append(lines, InlineLine{Kind: ReportKindEqual, Old: line, New: line})
+	}
+	return lines
+}
+
+// filter returns the segments without the given kind.
+func (s Segments) filter(without ReportKind) Segments {
+	filtered := Segments{}
+	for _, segment := range s {
+		if segment.Kind != without {
+			filtered = append(filtered, segment)
+		}
+	}
+	return filtered
+}
+
+// splitLinesTrimmed returns the lines of SplitLines without their "\n" or "\r\n" terminators.
+func splitLinesTrimmed(full []string) []string {
+	lines := make([]string, len(full))
+	for i, line := range full {
+		if strings.HasSuffix(line, "\n") {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		}
+		lines[i] = line
+	}
+	return lines
+}
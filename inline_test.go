@@ -0,0 +1,218 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffWords(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name string
+		args args
+		want Segments
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: "the quick fox",
+				b: "the quick fox",
+			},
+			want: Segments{
+				{Kind: ReportKindEqual, Text: "the quick fox"},
+			},
+		},
+		{
+			name: "changed word",
+			args: args{
+				a: "the quick brown fox",
+				b: "the slow brown fox",
+			},
+			want: Segments{
+				{Kind: ReportKindEqual, Text: "the "},
+				{Kind: ReportKindDeleted, Text: "quick"},
+				{Kind: ReportKindInserted, Text: "slow"},
+				{Kind: ReportKindEqual, Text: " brown fox"},
+			},
+		},
+		{
+			name: "punctuation is a word of its own",
+			args: args{
+				a: "level=info msg=started",
+				b: "level=warn msg=started.",
+			},
+			want: Segments{
+				{Kind: ReportKindEqual, Text: "level="},
+				{Kind: ReportKindDeleted, Text: "info"},
+				{Kind: ReportKindInserted, Text: "warn"},
+				{Kind: ReportKindEqual, Text: " msg=started"},
+				{Kind: ReportKindInserted, Text: "."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffWords(tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffWords() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffChars(t *testing.T) {
+	got := DiffChars("grün", "grÜn")
+	want := Segments{
+		{Kind: ReportKindEqual, Text: "gr"},
+		{Kind: ReportKindDeleted, Text: "ü"},
+		{Kind: ReportKindInserted, Text: "Ü"},
+		{Kind: ReportKindEqual, Text: "n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffChars() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSegments_CleanupSemantic(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments Segments
+		want     Segments
+	}{
+		{
+			name:     "empty",
+			segments: Segments{},
+			want:     Segments{},
+		},
+		{
+			name:     "trivial equality is eliminated",
+			segments: DiffChars("mouse", "sofa"),
+			want: Segments{
+				{Kind: ReportKindDeleted, Text: "mouse"},
+				{Kind: ReportKindInserted, Text: "sofa"},
+			},
+		},
+		{
+			name: "long equality is kept",
+			segments: Segments{
+				{Kind: ReportKindDeleted, Text: "a"},
+				{Kind: ReportKindInserted, Text: "b"},
+				{Kind: ReportKindEqual, Text: "common"},
+				{Kind: ReportKindDeleted, Text: "c"},
+			},
+			want: Segments{
+				{Kind: ReportKindDeleted, Text: "a"},
+				{Kind: ReportKindInserted, Text: "b"},
+				{Kind: ReportKindEqual, Text: "common"},
+				{Kind: ReportKindDeleted, Text: "c"},
+			},
+		},
+		{
+			name: "equalities at the edges are kept",
+			segments: Segments{
+				{Kind: ReportKindEqual, Text: "a"},
+				{Kind: ReportKindDeleted, Text: "bcd"},
+				{Kind: ReportKindEqual, Text: "e"},
+			},
+			want: Segments{
+				{Kind: ReportKindEqual, Text: "a"},
+				{Kind: ReportKindDeleted, Text: "bcd"},
+				{Kind: ReportKindEqual, Text: "e"},
+			},
+		},
+		{
+			name: "adjacent runs are merged",
+			segments: Segments{
+				{Kind: ReportKindInserted, Text: "x"},
+				{Kind: ReportKindDeleted, Text: "a"},
+				{Kind: ReportKindInserted, Text: "y"},
+				{Kind: ReportKindEqual, Text: ""},
+				{Kind: ReportKindEqual, Text: "long"},
+				{Kind: ReportKindEqual, Text: "er"},
+			},
+			want: Segments{
+				{Kind: ReportKindDeleted, Text: "a"},
+				{Kind: ReportKindInserted, Text: "xy"},
+				{Kind: ReportKindEqual, Text: "longer"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.segments.CleanupSemantic()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CleanupSemantic() = %+v, want %+v", got, tt.want)
+			}
+			if got.Old() != tt.segments.Old() || got.New() != tt.segments.New() {
+				t.Errorf("CleanupSemantic() changed the texts: %q -> %q", got.Old(), got.New())
+			}
+		})
+	}
+}
+
+func TestDiffInline(t *testing.T) {
+	got := DiffInline("keep\nerror: file not found\nremoved\n", "keep\nerror: file not saved\nadded\r\n")
+	want := []InlineLine{
+		{
+			Kind: ReportKindEqual,
+			Old:  Segments{{Kind: ReportKindEqual, Text: "keep"}},
+			New:  Segments{{Kind: ReportKindEqual, Text: "keep"}},
+		},
+		{
+			Kind: ReportKindChanged,
+			Old: Segments{
+				{Kind: ReportKindEqual, Text: "error: file not "},
+				{Kind: ReportKindDeleted, Text: "foun"},
+				{Kind: ReportKindEqual, Text: "d"},
+			},
+			New: Segments{
+				{Kind: ReportKindEqual, Text: "error: file not "},
+				{Kind: ReportKindInserted, Text: "save"},
+				{Kind: ReportKindEqual, Text: "d"},
+			},
+		},
+		{
+			Kind: ReportKindChanged,
+			Old:  Segments{{Kind: ReportKindDeleted, Text: "remov"}, {Kind: ReportKindEqual, Text: "ed"}, {Kind: ReportKindDeleted, Text: "\n"}},
+			New:  Segments{{Kind: ReportKindInserted, Text: "add"}, {Kind: ReportKindEqual, Text: "ed"}, {Kind: ReportKindInserted, Text: "\r\n"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffInline() = %+v, want %+v", got, want)
+	}
+
+	got = DiffInline("a\r\nb", "a\nb")
+	want = []InlineLine{
+		{
+			Kind: ReportKindChanged,
+			Old:  Segments{{Kind: ReportKindEqual, Text: "a"}, {Kind: ReportKindDeleted, Text: "\r\n"}},
+			New:  Segments{{Kind: ReportKindEqual, Text: "a"}, {Kind: ReportKindInserted, Text: "\n"}},
+		},
+		{
+			Kind: ReportKindEqual,
+			Old:  Segments{{Kind: ReportKindEqual, Text: "b"}},
+			New:  Segments{{Kind: ReportKindEqual, Text: "b"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffInline() = %+v, want %+v", got, want)
+	}
+
+	got = DiffInline("a\nb\n", "a\n")
+	want = []InlineLine{
+		{
+			Kind: ReportKindEqual,
+			Old:  Segments{{Kind: ReportKindEqual, Text: "a"}},
+			New:  Segments{{Kind: ReportKindEqual, Text: "a"}},
+		},
+		{
+			Kind: ReportKindDeleted,
+			Old:  Segments{{Kind: ReportKindDeleted, Text: "b"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffInline() = %+v, want %+v", got, want)
+	}
+}
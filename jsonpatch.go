@@ -0,0 +1,571 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidJSON is returned when a document is not valid JSON.
+	ErrInvalidJSON = errors.New("invalid json")
+	// ErrInvalidJSONPatch is returned when a JSON patch operation is malformed.
+	ErrInvalidJSONPatch = errors.New("invalid json patch")
+	// ErrInvalidJSONPointer is returned when a JSON pointer is malformed or does not resolve.
+	ErrInvalidJSONPointer = errors.New("invalid json pointer")
+	// ErrJSONPatchTestFailed is returned when the value of a "test" operation does not match.
+	ErrJSONPatchTestFailed = errors.New("json patch test failed")
+)
+
+// JSONPatchOp defines the operation of a JSON patch operation as defined by RFC 6902.
+type JSONPatchOp string
+
+const (
+	// JSONPatchAdd adds a value to an object or inserts it into an array.
+	JSONPatchAdd JSONPatchOp = "add"
+	// JSONPatchRemove removes the value at the path.
+	JSONPatchRemove JSONPatchOp = "remove"
+	// JSONPatchReplace replaces the value at the path.
+	JSONPatchReplace JSONPatchOp = "replace"
+	// JSONPatchMove removes the value at from and adds it at the path.
+	JSONPatchMove JSONPatchOp = "move"
+	// JSONPatchCopy copies the value at from to the path.
+	JSONPatchCopy JSONPatchOp = "copy"
+	// JSONPatchTest checks that the value at the path is equal to the value.
+	JSONPatchTest JSONPatchOp = "test"
+)
+
+// JSONPatch defines a list of operations as defined by RFC 6902.
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOperation defines a single operation of a JSON patch.
+type JSONPatchOperation struct {
+	// Op defines the operation.
+	Op JSONPatchOp `json:"op"`
+	// Path defines the JSON pointer (RFC 6901) the operation is applied to.
+	Path string `json:"path"`
+	// From defines the JSON pointer of the source value of "move" and "copy".
+	From string `json:"from,omitempty"`
+	// Value defines the value of "add", "replace" and "test". A nil value is encoded as null.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes the operation. The value is always written for
+// "add", "replace" and "test", even if it is null.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Op    JSONPatchOp      `json:"op"`
+		From  *string          `json:"from,omitempty"`
+		Path  string           `json:"path"`
+		Value *json.RawMessage `json:"value,omitempty"`
+	}{Op: o.Op, Path: o.Path}
+	switch o.Op {
+	case JSONPatchMove, JSONPatchCopy:
+		out.From = &o.From
+	case JSONPatchAdd, JSONPatchReplace, JSONPatchTest:
+		value, err := encodeJSON(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(value)
+		out.Value = &raw
+	}
+	return json.Marshal(out)
+}
+
+// ParseJSONPatch parses a JSON patch document and validates its operations.
+// Numbers are kept as json.Number to preserve their precision.
+func ParseJSONPatch(data []byte) (JSONPatch, error) {
+	raw := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSONPatch, err)
+	}
+	patch := make(JSONPatch, 0, len(raw))
+	for i, fields := range raw {
+		op := JSONPatchOperation{}
+		if err := json.Unmarshal(fields["op"], &op.Op); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: missing op", ErrInvalidJSONPatch, i)
+		}
+		if err := json.Unmarshal(fields["path"], &op.Path); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: missing path", ErrInvalidJSONPatch, i)
+		}
+		switch op.Op {
+		case JSONPatchAdd, JSONPatchReplace, JSONPatchTest:
+			value, ok := fields["value"]
+			if !ok {
+				return nil, fmt.Errorf("%w: operation %d: missing value", ErrInvalidJSONPatch, i)
+			}
+			decoded, err := decodeJSON(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalidJSONPatch, i, err)
+			}
+			op.Value = decoded
+		case JSONPatchMove, JSONPatchCopy:
+			if err := json.Unmarshal(fields["from"], &op.From); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: missing from", ErrInvalidJSONPatch, i)
+			}
+		case JSONPatchRemove:
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown op %q", ErrInvalidJSONPatch, i, op.Op)
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+
+// CreateJSONPatch returns the patch that transforms the JSON document a into b.
+// Object members are visited in sorted order, members that were renamed without
+// changing their value become "move" operations. Arrays are aligned with the shortest
+// edit script, so inserting an element produces a single "add" operation.
+func CreateJSONPatch(a, b []byte) (JSONPatch, error) {
+	aValue, err := decodeJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	bValue, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	patch := JSONPatch{}
+	createJSONPatch(&patch, "", aValue, bValue)
+	return patch, nil
+}
+
+// createJSONPatch appends the operations that transform a into b at path.
+func createJSONPatch(patch *JSONPatch, path string, a, b interface{}) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			createJSONObjectPatch(patch, path, av, bv)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			createJSONArrayPatch(patch, path, av, bv)
+			return
+		}
+	}
+	if !jsonEqual(a, b) {
+		*patch = append(*patch, JSONPatchOperation{Op: JSONPatchReplace, Path: path, Value: b})
+	}
+}
+
+// createJSONObjectPatch appends the operations that transform the object a into b.
+func createJSONObjectPatch(patch *JSONPatch, path string, a, b map[string]interface{}) {
+	removed, added := []string{}, []string{}
+	for _, key := range sortedJSONKeys(a) {
+		if _, ok := b[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	for _, key := range sortedJSONKeys(b) {
+		if _, ok := a[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	// renamed members
+	moved := map[string]bool{}
+	for _, from := range removed {
+		for _, to := range added {
+			if !moved[to] && jsonEqual(a[from], b[to]) {
+				*patch = append(*patch, JSONPatchOperation{Op: JSONPatchMove, From: jsonPointerJoin(path, from), Path: jsonPointerJoin(path, to)})
+				moved[from], moved[to] = true, true
+				break
+			}
+		}
+	}
+	for _, key := range removed {
+		if !moved[key] {
+			*patch = append(*patch, JSONPatchOperation{Op: JSONPatchRemove, Path: jsonPointerJoin(path, key)})
+		}
+	}
+	for _, key := range sortedJSONKeys(a) {
+		if bv, ok := b[key]; ok {
+			createJSONPatch(patch, jsonPointerJoin(path, key), a[key], bv)
+		}
+	}
+	for _, key := range added {
+		if !moved[key] {
+			*patch = append(*patch, JSONPatchOperation{Op: JSONPatchAdd, Path: jsonPointerJoin(path, key), Value: b[key]})
+		}
+	}
+}
+
+// createJSONArrayPatch appends the operations that transform the array a into b.
+// The operations are applied in order, so the indices refer to the partially patched array.
+func createJSONArrayPatch(patch *JSONPatch, path string, a, b []interface{}) {
+	script := DiffSequence(len(a), len(b), func(i, j int) bool {
+		return jsonEqual(a[i], b[j])
+	})
+	// pos is the index in the patched array that corresponds to the next element of a
+	pos := 0
+	for k := 0; k < len(script); k++ {
+		edit := script[k]
+		switch edit.Op {
+		case EditEqual:
+			pos += edit.AEnd - edit.AStart
+		case EditDelete:
+			deleted := edit.AEnd - edit.AStart
+			inserted := 0
+			var insert Edit
+			if k+1 < len(script) && script[k+1].Op == EditInsert {
+				insert = script[k+1]
+				inserted = insert.BEnd - insert.BStart
+				k++
+			}
+			paired := deleted
+			if inserted < paired {
+				paired = inserted
+			}
+			for n := 0; n < paired; n++ {
+				createJSONPatch(patch, jsonPointerJoin(path, strconv.Itoa(pos)), a[edit.AStart+n], b[insert.BStart+n])
+				pos++
+			}
+			for n := paired; n < deleted; n++ {
+				*patch = append(*patch, JSONPatchOperation{Op: JSONPatchRemove, Path: jsonPointerJoin(path, strconv.Itoa(pos))})
+			}
+			for n := paired; n < inserted; n++ {
+				*patch = append(*patch, JSONPatchOperation{Op: JSONPatchAdd, Path: jsonPointerJoin(path, strconv.Itoa(pos)), Value: b[insert.BStart+n]})
+				pos++
+			}
+		case EditInsert:
+			for n := edit.BStart; n < edit.BEnd; n++ {
+				*patch = append(*patch, JSONPatchOperation{Op: JSONPatchAdd, Path: jsonPointerJoin(path, strconv.Itoa(pos)), Value: b[n]})
+				pos++
+			}
+		}
+	}
+}
+
+// ApplyJSONPatch applies the patch to the JSON document and returns the patched document.
+// The operations are applied in order. If an operation fails, the error is returned
+// and none of the operations take effect.
+func ApplyJSONPatch(doc []byte, patch JSONPatch) ([]byte, error) {
+	value, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range patch {
+		value, err = applyJSONPatchOperation(value, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return encodeJSON(value)
+}
+
+// applyJSONPatchOperation applies a single operation to the document and returns the new document.
+func applyJSONPatchOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case JSONPatchAdd:
+		return jsonAdd(doc, path, copyJSON(op.Value))
+	case JSONPatchRemove:
+		doc, _, err = jsonRemove(doc, path)
+		return doc, err
+	case JSONPatchReplace:
+		if _, err := jsonGet(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return copyJSON(op.Value), nil
+		}
+		doc, _, err = jsonRemove(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		return jsonAdd(doc, path, copyJSON(op.Value))
+	case JSONPatchMove:
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("%w: cannot move %q into its own child %q", ErrInvalidJSONPatch, op.From, op.Path)
+		}
+		if op.Path == op.From {
+			_, err := jsonGet(doc, from)
+			return doc, err
+		}
+		doc, value, err := jsonRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return jsonAdd(doc, path, value)
+	case JSONPatchCopy:
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := jsonGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return jsonAdd(doc, path, copyJSON(value))
+	case JSONPatchTest:
+		value, err := jsonGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, op.Value) {
+			return nil, ErrJSONPatchTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidJSONPatch, op.Op)
+}
+
+// jsonGet returns the value the pointer refers to.
+func jsonGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalidJSONPointer, token)
+			}
+			doc = value
+		case []interface{}:
+			idx, err := jsonArrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[idx]
+		default:
+			return nil, fmt.Errorf("%w: %q refers into a scalar value", ErrInvalidJSONPointer, token)
+		}
+	}
+	return doc, nil
+}
+
+// jsonUpdate calls fn with the parent container of the pointer and the last reference token
+// and stores the container returned by fn in the document.
+func jsonUpdate(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := jsonGet(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = jsonUpdate(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		idx, _ := jsonArrayIndex(path[0], len(node)-1)
+		node[idx] = child
+	}
+	return doc, nil
+}
+
+// jsonAdd adds the value at the pointer and returns the new document.
+func jsonAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return jsonUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			idx := len(node)
+			if token != "-" {
+				var err error
+				if idx, err = jsonArrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[idx+1:], node[idx:])
+			node[idx] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add %q to a scalar value", ErrInvalidJSONPointer, token)
+	})
+}
+
+// jsonRemove removes the value at the pointer and returns the new document and the removed value.
+func jsonRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the root", ErrInvalidJSONPointer)
+	}
+	var removed interface{}
+	doc, err := jsonUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalidJSONPointer, token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			idx, err := jsonArrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[idx]
+			return append(node[:idx:idx], node[idx+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove %q from a scalar value", ErrInvalidJSONPointer, token)
+	})
+	return doc, removed, err
+}
+
+// jsonArrayIndex parses an array index that must not exceed max.
+func jsonArrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalidJSONPointer, token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx > max {
+		return 0, fmt.Errorf("%w: index %s is out of range", ErrInvalidJSONPointer, token)
+	}
+	return idx, nil
+}
+
+// parseJSONPointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q does not start with /", ErrInvalidJSONPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if strings.Count(token, "~") != strings.Count(token, "~0")+strings.Count(token, "~1") {
+			return nil, fmt.Errorf("%w: invalid escape in %q", ErrInvalidJSONPointer, pointer)
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// jsonPointerJoin appends the escaped reference token to the pointer.
+func jsonPointerJoin(pointer, token string) string {
+	return pointer + "/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// decodeJSON decodes a single JSON value. Numbers are decoded as json.Number.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the value", ErrInvalidJSON)
+	}
+	return value, nil
+}
+
+// encodeJSON encodes the value without escaping HTML characters.
+func encodeJSON(value interface{}) ([]byte, error) {
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonEqual reports whether the decoded JSON values a and b are equal.
+// Numbers are equal if they have the same numeric value, e.g. 1 and 1.0.
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number, float64:
+		af, aok := jsonNumber(a)
+		bf, bok := jsonNumber(b)
+		if !aok || !bok {
+			return false
+		}
+		if an, ok := a.(json.Number); ok {
+			if bn, ok := b.(json.Number); ok && an == bn {
+				return true
+			}
+		}
+		return af == bf
+	}
+	return a == b
+}
+
+// jsonNumber converts a decoded JSON number into a float64.
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// copyJSON returns a deep copy of the decoded JSON value.
+func copyJSON(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			out[key] = copyJSON(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(node))
+		for i, value := range node {
+			out[i] = copyJSON(value)
+		}
+		return out
+	}
+	return v
+}
+
+// sortedJSONKeys returns the member names of the object in sorted order.
+func sortedJSONKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
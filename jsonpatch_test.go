@@ -0,0 +1,355 @@
+package compare
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCreateJSONPatch(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: `{"a":1,"b":[1,2]}`,
+				b: `{"b":[1,2],"a":1.0}`,
+			},
+			want:    `[]`,
+			wantErr: nil,
+		},
+		{
+			name: "object members",
+			args: args{
+				a: `{"name":"a","email":"a@x","old":true}`,
+				b: `{"name":"a","email":"b@x","new":null}`,
+			},
+			want:    `[{"op":"remove","path":"/old"},{"op":"replace","path":"/email","value":"b@x"},{"op":"add","path":"/new","value":null}]`,
+			wantErr: nil,
+		},
+		{
+			name: "renamed member is moved",
+			args: args{
+				a: `{"a/b":{"x":1}}`,
+				b: `{"c~d":{"x":1}}`,
+			},
+			want:    `[{"op":"move","from":"/a~1b","path":"/c~0d"}]`,
+			wantErr: nil,
+		},
+		{
+			name: "array elements",
+			args: args{
+				a: `{"users":[{"email":"a@x"},{"email":"b@x"},{"email":"c@x"}]}`,
+				b: `{"users":[{"email":"z@x"},{"email":"a@x"},{"email":"b@x"},{"email":"d@x"}]}`,
+			},
+			want:    `[{"op":"add","path":"/users/0","value":{"email":"z@x"}},{"op":"replace","path":"/users/3/email","value":"d@x"}]`,
+			wantErr: nil,
+		},
+		{
+			name: "different root types",
+			args: args{
+				a: `[1]`,
+				b: `{"a":1}`,
+			},
+			want:    `[{"op":"replace","path":"","value":{"a":1}}]`,
+			wantErr: nil,
+		},
+		{
+			name: "invalid json",
+			args: args{
+				a: `{`,
+				b: `{}`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateJSONPatch([]byte(tt.args.a), []byte(tt.args.b))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(encoded) != tt.want {
+				t.Errorf("CreateJSONPatch() = %s, want %s", encoded, tt.want)
+			}
+			patched, err := ApplyJSONPatch([]byte(tt.args.a), got)
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+			assertJSONEqual(t, patched, tt.args.b)
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	type args struct {
+		doc   string
+		patch string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "add object member",
+			args: args{
+				doc:   `{"foo":"bar"}`,
+				patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			},
+			want:    `{"baz":"qux","foo":"bar"}`,
+			wantErr: nil,
+		},
+		{
+			name: "add array element",
+			args: args{
+				doc:   `{"foo":["bar","baz"]}`,
+				patch: `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":"end"}]`,
+			},
+			want:    `{"foo":["bar","qux","baz","end"]}`,
+			wantErr: nil,
+		},
+		{
+			name: "remove and replace",
+			args: args{
+				doc:   `{"baz":"qux","foo":["bar","qux","baz"]}`,
+				patch: `[{"op":"remove","path":"/foo/1"},{"op":"replace","path":"/baz","value":"boo"}]`,
+			},
+			want:    `{"baz":"boo","foo":["bar","baz"]}`,
+			wantErr: nil,
+		},
+		{
+			name: "move and copy",
+			args: args{
+				doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+				patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"},{"op":"copy","from":"/qux","path":"/copy"}]`,
+			},
+			want:    `{"copy":{"corge":"grault","thud":"fred"},"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+			wantErr: nil,
+		},
+		{
+			name: "escaped pointer",
+			args: args{
+				doc:   `{"a/b":1,"m~n":2}`,
+				patch: `[{"op":"test","path":"/a~1b","value":1.0},{"op":"replace","path":"/m~0n","value":3}]`,
+			},
+			want:    `{"a/b":1,"m~n":3}`,
+			wantErr: nil,
+		},
+		{
+			name: "replace root",
+			args: args{
+				doc:   `{"a":1}`,
+				patch: `[{"op":"replace","path":"","value":[1,2]}]`,
+			},
+			want:    `[1,2]`,
+			wantErr: nil,
+		},
+		{
+			name: "large numbers keep their precision",
+			args: args{
+				doc:   `{"id":12345678901234567890}`,
+				patch: `[{"op":"add","path":"/copy","value":98765432109876543210}]`,
+			},
+			want:    `{"copy":98765432109876543210,"id":12345678901234567890}`,
+			wantErr: nil,
+		},
+		{
+			name: "failed test",
+			args: args{
+				doc:   `{"baz":"qux"}`,
+				patch: `[{"op":"replace","path":"/baz","value":"x"},{"op":"test","path":"/baz","value":"qux"}]`,
+			},
+			want:    ``,
+			wantErr: ErrJSONPatchTestFailed,
+		},
+		{
+			name: "missing member",
+			args: args{
+				doc:   `{"foo":"bar"}`,
+				patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSONPointer,
+		},
+		{
+			name: "index out of range",
+			args: args{
+				doc:   `[1,2]`,
+				patch: `[{"op":"add","path":"/3","value":3}]`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSONPointer,
+		},
+		{
+			name: "index with leading zero",
+			args: args{
+				doc:   `[1,2]`,
+				patch: `[{"op":"remove","path":"/01"}]`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSONPointer,
+		},
+		{
+			name: "move into own child",
+			args: args{
+				doc:   `{"a":{"b":{}}}`,
+				patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSONPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParseJSONPatch([]byte(tt.args.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch() error = %v", err)
+			}
+			got, err := ApplyJSONPatch([]byte(tt.args.doc), patch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplyJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("ApplyJSONPatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    JSONPatch
+		wantErr error
+	}{
+		{
+			name: "null value",
+			in:   `[{"op":"add","path":"/a","value":null},{"op":"copy","from":"/a","path":"/b"}]`,
+			want: JSONPatch{
+				{Op: JSONPatchAdd, Path: "/a", Value: nil},
+				{Op: JSONPatchCopy, From: "/a", Path: "/b"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "missing value",
+			in:      `[{"op":"add","path":"/a"}]`,
+			want:    nil,
+			wantErr: ErrInvalidJSONPatch,
+		},
+		{
+			name:    "missing from",
+			in:      `[{"op":"move","path":"/a"}]`,
+			want:    nil,
+			wantErr: ErrInvalidJSONPatch,
+		},
+		{
+			name:    "unknown op",
+			in:      `[{"op":"merge","path":"/a"}]`,
+			want:    nil,
+			wantErr: ErrInvalidJSONPatch,
+		},
+		{
+			name:    "not an array",
+			in:      `{"op":"add"}`,
+			want:    nil,
+			wantErr: ErrInvalidJSONPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSONPatch([]byte(tt.in))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseJSONPatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatch_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a, _ := json.Marshal(randomJSONValue(rnd, 3))
+		b, _ := json.Marshal(randomJSONValue(rnd, 3))
+		patch, err := CreateJSONPatch(a, b)
+		if err != nil {
+			t.Fatalf("CreateJSONPatch() error = %v", err)
+		}
+		encoded, _ := json.Marshal(patch)
+		parsed, err := ParseJSONPatch(encoded)
+		if err != nil {
+			t.Fatalf("ParseJSONPatch(%s) error = %v", encoded, err)
+		}
+		patched, err := ApplyJSONPatch(a, parsed)
+		if err != nil {
+			t.Fatalf("ApplyJSONPatch(%s, %s) error = %v", a, encoded, err)
+		}
+		assertJSONEqual(t, patched, string(b))
+	}
+}
+
+// randomJSONValue returns a small random JSON value with shared keys and elements,
+// so that generated documents overlap.
+func randomJSONValue(rnd *rand.Rand, depth int) interface{} {
+	switch k := rnd.Intn(6); {
+	case depth > 0 && k < 2:
+		m := map[string]interface{}{}
+		for i := rnd.Intn(4); i > 0; i-- {
+			m[string(rune('a'+rnd.Intn(4)))] = randomJSONValue(rnd, depth-1)
+		}
+		return m
+	case depth > 0 && k < 4:
+		s := []interface{}{}
+		for i := rnd.Intn(5); i > 0; i-- {
+			s = append(s, randomJSONValue(rnd, depth-1))
+		}
+		return s
+	case k == 4:
+		return nil
+	default:
+		return strconv.Itoa(rnd.Intn(3))
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	gotValue, err := decodeJSON(got)
+	if err != nil {
+		t.Fatalf("decodeJSON(%s) error = %v", got, err)
+	}
+	wantValue, err := decodeJSON([]byte(want))
+	if err != nil {
+		t.Fatalf("decodeJSON(%s) error = %v", want, err)
+	}
+	if !jsonEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 var (
 	ErrInvalidMatchType = errors.New("invalid match type")
 	ErrValueNotANumber  = errors.New("value is not a number")
+	ErrValueNotAString  = errors.New("value has no string form")
 )
 
 // MatchType defines the type of match to be performed.
@@ -43,6 +45,9 @@ const (
 	MatchTypeGreaterThanOrEqual MatchType = "gte"
 	// MatchTypePercentageDeviation is used to compare the response with the expected value.
 	// A percentage offset is calculated from the expected value and the response.
+	// The offset is the share of changed, inserted and deleted bytes of the shortest edit script
+	// between the JSON encoded expected value and response. The edit script is only computed
+	// up to twice the number of bytes the percentage offset tolerates, longer scripts exceed it.
 	// If the percentage offset is smaller than the calculated offset, the validation is successful.
 	// The defined offset is added to the expected value and defines a failure tolerance.
 	MatchTypePercentageDeviation MatchType = "pd"
@@ -74,6 +79,34 @@ const (
 	// If the response contains the expected value the validation is successful.
 	// If the response does not contain the expected value the validation is not successful.
 	MatchTypeContains MatchType = "ct"
+	// MatchTypeStartsWith is used to compare the response with the match value.
+	// If the string form of the response starts with the match value the validation is successful.
+	// If the response has no string form the validation is not successful.
+	MatchTypeStartsWith MatchType = "sw"
+	// MatchTypeStartsWithIgnoreCase is the case-insensitive variant of MatchTypeStartsWith.
+	MatchTypeStartsWithIgnoreCase MatchType = "swi"
+	// MatchTypeEndsWith is used to compare the response with the match value.
+	// If the string form of the response ends with the match value the validation is successful.
+	// If the response has no string form the validation is not successful.
+	MatchTypeEndsWith MatchType = "ew"
+	// MatchTypeEndsWithIgnoreCase is the case-insensitive variant of MatchTypeEndsWith.
+	MatchTypeEndsWithIgnoreCase MatchType = "ewi"
+	// MatchTypeGlob is used to compare the response with the match value.
+	// The match value is a shell-style glob pattern ("*", "?", "[a-z]" and "**" for path segments).
+	// If the string form of the response matches the pattern the validation is successful.
+	// If the response has no string form the validation is not successful.
+	MatchTypeGlob MatchType = "gb"
+	// MatchTypeGlobIgnoreCase is the case-insensitive variant of MatchTypeGlob.
+	MatchTypeGlobIgnoreCase MatchType = "gbi"
+	// MatchTypeShape is used to compare the response with the expected value.
+	// The expected value is a partial map or struct, only the keys it lists are compared, recursively.
+	// Leaves of the expected value may be nested validations.
+	// If the response contains all listed keys with matching values the validation is successful.
+	// Additional keys of the response are ignored. Zero fields of struct shapes are not compared,
+	// use pointers or nested validations to compare zero values.
+	// Numbers of a response decoded by encoding/json (float64 or json.Number) are accepted by
+	// nested numeric validations.
+	MatchTypeShape MatchType = "sh"
 )
 
 // Validation defines the validation specification to execute a test.
@@ -92,6 +125,14 @@ type Validation struct {
 	// - ne: not empty
 	// - et: empty
 	// - ct: contains
+	// - sw: starts with
+	// - swi: starts with (case-insensitive)
+	// - ew: ends with
+	// - ewi: ends with (case-insensitive)
+	// - gb: glob
+	// - gbi: glob (case-insensitive)
+	// - sh: matches shape
+	// - any match type registered in the registry of the match options
 	MatchType MatchType
 	// MatchValue defines the value operation.
 	// Must only be set for "percentual offset" and "range" definitions.
@@ -99,55 +140,63 @@ type Validation struct {
 	// - [0-9]-[0-9]: range definition
 	// - [0-9]%: percentual offset
 	// - any: regex
+	// - any: prefix, suffix or glob pattern
 	MatchValue *string
 	// ExpectedValue defines the expected value.
 	ExpectedValue interface{}
+	// EqualOptions defines how "equals" and "not equals" compare values.
+	// If nil, reflect.DeepEqual is used.
+	EqualOptions *EqualOptions
+}
+
+// MatchOptions configures how a validation is evaluated.
+// The options are passed next to the validation, so that Validation stays a plain value
+// that can be compared and serialized.
+type MatchOptions struct {
+	// Normalizers defines an optional pipeline that is applied to string values
+	// before "equals", "not equals" and "contains" are evaluated.
+	// Strings, named string types and byte slices are normalized,
+	// all other values are compared as is.
+	Normalizers Normalizers
+	// Registry defines the registry used to resolve custom match types.
+	// If nil, the DefaultRegistry is used.
+	Registry *Registry
 }
 
 // Matches validates the argument value against the validation specification.
 // If the validation is successful the method returns true as validation and nil as error.
 func (d Validation) Matches(value interface{}) (bool, error) {
+	return d.MatchesWithOptions(value, MatchOptions{})
+}
+
+// MatchesWithOptions validates the argument value against the validation specification
+// like Matches and applies the options. Nested validations of "matches shape" use the same options.
+func (d Validation) MatchesWithOptions(value interface{}, opts MatchOptions) (bool, error) {
 	switch d.MatchType {
 	case MatchTypeLessThan:
-		val1, err := valueToInt64(d.ExpectedValue)
+		cmp, err := compareNumbers(value, d.ExpectedValue)
 		if err != nil {
 			return false, err
 		}
-		val2, err := valueToInt64(value)
-		if err != nil {
-			return false, err
-		}
-		return val2 < val1, nil
+		return cmp < 0, nil
 	case MatchTypeLessThanOrEqual:
-		val1, err := valueToInt64(d.ExpectedValue)
+		cmp, err := compareNumbers(value, d.ExpectedValue)
 		if err != nil {
 			return false, err
 		}
-		val2, err := valueToInt64(value)
-		if err != nil {
-			return false, err
-		}
-		return val2 <= val1, nil
+		return cmp <= 0, nil
 	case MatchTypeGreaterThan:
-		val1, err := valueToInt64(d.ExpectedValue)
+		cmp, err := compareNumbers(value, d.ExpectedValue)
 		if err != nil {
 			return false, err
 		}
-		val2, err := valueToInt64(value)
-		if err != nil {
-			return false, err
-		}
-		return val2 > val1, nil
+		return cmp > 0, nil
 	case MatchTypeGreaterThanOrEqual:
-		val1, err := valueToInt64(d.ExpectedValue)
-		if err != nil {
-			return false, err
-		}
-		val2, err := valueToInt64(value)
+		cmp, err := compareNumbers(value, d.ExpectedValue)
 		if err != nil {
 			return false, err
 		}
-		return val2 >= val1, nil
+		return cmp >= 0, nil
 	case MatchTypePercentageDeviation:
 		btsFromValue, err := json.Marshal(value)
 		if err != nil {
@@ -157,19 +206,11 @@ func (d Validation) Matches(value interface{}) (bool, error) {
 		if err != nil {
 			return false, err
 		}
-		reports, err := BytesDifferent(btsFromValue, btsFromExpectedValue)
-		if err != nil {
-			return false, err
-		}
-		pcnt, err := NewPercentFromFloats(len(btsFromExpectedValue), len(reports))
-		if err != nil {
-			return false, err
-		}
 		pcntFromMatchValue, err := ParsePercentageValueFromString(*d.MatchValue)
 		if err != nil {
 			return false, err
 		}
-		return pcnt.Get() <= pcntFromMatchValue.Get(), nil
+		return deviationWithin(btsFromExpectedValue, btsFromValue, pcntFromMatchValue.Get())
 	case MatchTypeRegex:
 		rp := regexp.MustCompile(*d.MatchValue)
 		buf := bytes.Buffer{}
@@ -180,15 +221,15 @@ func (d Validation) Matches(value interface{}) (bool, error) {
 		return rp.Match(buf.Bytes()), nil
 	case MatchTypeRange:
 		r := rangeParser(*d.MatchValue)
-		val1, err := valueToInt64(value)
+		val, err := valueToNumber(value)
 		if err != nil {
 			return false, err
 		}
-		return val1 >= r[0] && val1 <= r[1], nil
+		return val.compare(number{i: r[0]}) >= 0 && val.compare(number{i: r[1]}) <= 0, nil
 	case MatchTypeEqual:
-		return reflect.DeepEqual(d.ExpectedValue, value), nil
+		return d.equal(value, opts.Normalizers), nil
 	case MatchTypeNotEqual:
-		return !reflect.DeepEqual(d.ExpectedValue, value), nil
+		return !d.equal(value, opts.Normalizers), nil
 	case MatchTypeNotEmpty:
 		if str, ok := value.(string); ok {
 			if str == "" {
@@ -206,24 +247,209 @@ func (d Validation) Matches(value interface{}) (bool, error) {
 		}
 		return value == nil, nil
 	case MatchTypeContains:
+		if str, ok := normalizableString(value); ok && len(opts.Normalizers) > 0 {
+			return strings.Contains(opts.Normalizers.Normalize(str), opts.Normalizers.Normalize(*d.MatchValue)), nil
+		}
 		buffer := bytes.Buffer{}
 		err := gob.NewEncoder(&buffer).Encode(value)
 		if err != nil {
 			return false, fmt.Errorf("%w: gob envoding failed for value = %v", err, value)
 		}
 		return strings.Contains(buffer.String(), *d.MatchValue), nil
+	case MatchTypeStartsWith, MatchTypeStartsWithIgnoreCase:
+		str, err := valueToString(value)
+		if err != nil {
+			return false, err
+		}
+		prefix, err := d.requiredMatchValue()
+		if err != nil {
+			return false, err
+		}
+		if d.MatchType == MatchTypeStartsWithIgnoreCase {
+			str, prefix = strings.ToLower(str), strings.ToLower(prefix)
+		}
+		return strings.HasPrefix(str, prefix), nil
+	case MatchTypeEndsWith, MatchTypeEndsWithIgnoreCase:
+		str, err := valueToString(value)
+		if err != nil {
+			return false, err
+		}
+		suffix, err := d.requiredMatchValue()
+		if err != nil {
+			return false, err
+		}
+		if d.MatchType == MatchTypeEndsWithIgnoreCase {
+			str, suffix = strings.ToLower(str), strings.ToLower(suffix)
+		}
+		return strings.HasSuffix(str, suffix), nil
+	case MatchTypeGlob:
+		str, err := valueToString(value)
+		if err != nil {
+			return false, err
+		}
+		pattern, err := d.requiredMatchValue()
+		if err != nil {
+			return false, err
+		}
+		return globMatch(pattern, str)
+	case MatchTypeGlobIgnoreCase:
+		str, err := valueToString(value)
+		if err != nil {
+			return false, err
+		}
+		pattern, err := d.requiredMatchValue()
+		if err != nil {
+			return false, err
+		}
+		return globMatchFold(pattern, str)
+	case MatchTypeShape:
+		return matchShape(d.ExpectedValue, value, opts)
 	default:
-		return false, fmt.Errorf("%w: %s", ErrInvalidMatchType, d.MatchType)
+		return d.matchRegistered(value, opts.registry())
 	}
 }
 
-// valueToInt64 converts the value to an int64.
-func valueToInt64(value interface{}) (int64, error) {
-	iVal, ok := value.(int64)
-	if !ok {
-		return 0, fmt.Errorf("%w: %v", ErrValueNotANumber, value)
+// requiredMatchValue returns the match value or ErrInvalidMatchValue if it is missing.
+func (d Validation) requiredMatchValue() (string, error) {
+	if d.MatchValue == nil {
+		return "", fmt.Errorf("%w: match type %s requires a match value", ErrInvalidMatchValue, d.MatchType)
+	}
+	return *d.MatchValue, nil
+}
+
+// equal reports whether the value equals the expected value.
+// If normalizers are given and both values are strings of the same type,
+// the normalized strings are compared. Otherwise the equality engine configured
+// by the equal options is used.
+func (d Validation) equal(value interface{}, normalizers Normalizers) bool {
+	if len(normalizers) > 0 && reflect.TypeOf(d.ExpectedValue) == reflect.TypeOf(value) {
+		expected, ok1 := normalizableString(d.ExpectedValue)
+		actual, ok2 := normalizableString(value)
+		if ok1 && ok2 {
+			return normalizers.Normalize(expected) == normalizers.Normalize(actual)
+		}
+	}
+	if d.EqualOptions != nil {
+		return DeepEqual(d.ExpectedValue, value, *d.EqualOptions)
+	}
+	return reflect.DeepEqual(d.ExpectedValue, value)
+}
+
+// deviationWithin reports whether the share of different bytes of value in expected is at
+// most threshold percent. The difference is based on the shortest edit script. Every different
+// byte accounts for at most two inserted and deleted bytes, so edit scripts longer than twice
+// the tolerated number of bytes exceed the threshold and are not computed. This bounds the
+// cost by O((n+m)*tolerated) instead of the size of the difference.
+func deviationWithin(expected, value []byte, threshold float64) (bool, error) {
+	// the deviation is capped at 100%
+	if threshold >= 100 {
+		return true, nil
+	}
+	tolerated := int(threshold / 100 * float64(len(expected)))
+	equal := func(i, j int) bool { return expected[i] == value[j] }
+	if _, ok := editDistanceWithin(len(expected), len(value), equal, 2*tolerated); !ok {
+		return false, nil
+	}
+	pcnt, err := BytesStats(expected, value).Deviation()
+	if err != nil {
+		return false, err
+	}
+	return pcnt.Get() <= threshold, nil
+}
+
+// number defines a numeric value. Integers are held as int64 to compare them exactly,
+// numbers with a fraction or beyond the range of int64 as float64.
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+// valueToNumber converts the value to a number.
+// Besides int64 it accepts the numbers produced by encoding/json: float64 and json.Number.
+func valueToNumber(value interface{}) (number, error) {
+	switch v := value.(type) {
+	case int64:
+		return number{i: v}, nil
+	case float64:
+		if !math.IsNaN(v) {
+			return number{f: v, isFloat: true}, nil
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return number{i: i}, nil
+		}
+		if f, err := v.Float64(); err == nil {
+			return number{f: f, isFloat: true}, nil
+		}
+	}
+	return number{}, fmt.Errorf("%w: %v", ErrValueNotANumber, value)
+}
+
+// compare returns -1, 0 or 1 if n is less than, equal to or greater than o.
+// Two integers are compared exactly, otherwise both numbers are compared as float64.
+func (n number) compare(o number) int {
+	if !n.isFloat && !o.isFloat {
+		switch {
+		case n.i < o.i:
+			return -1
+		case n.i > o.i:
+			return 1
+		}
+		return 0
+	}
+	a, b := n.float64(), o.float64()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// float64 returns the number as float64.
+func (n number) float64() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+// compareNumbers converts a and b to numbers and compares them, see number.compare.
+func compareNumbers(a, b interface{}) (int, error) {
+	x, err := valueToNumber(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := valueToNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return x.compare(y), nil
+}
+
+// valueToString returns the native string form of the value.
+// Strings and byte slices are returned as is, fmt.Stringer and error use their
+// own representation, booleans and numbers are formatted in base 10 and named
+// string types are converted to their underlying string.
+func valueToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case error:
+		return v.Error(), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String(), nil
 	}
-	return iVal, nil
+	return "", fmt.Errorf("%w: %v", ErrValueNotAString, value)
 }
 
 // rangeParser parses the range definition.
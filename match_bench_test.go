@@ -124,3 +124,27 @@ func BenchmarkValidation_Matches_MatchTypeContains(b *testing.B) {
 		}.Matches("abc")
 	}
 }
+
+func BenchmarkValidation_Matches_MatchTypeStartsWith(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Validation{
+			MatchType: MatchTypeStartsWith,
+			MatchValue: func() *string {
+				str := "api."
+				return &str
+			}(),
+		}.Matches("api.example.com")
+	}
+}
+
+func BenchmarkValidation_Matches_MatchTypeGlob(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Validation{
+			MatchType: MatchTypeGlob,
+			MatchValue: func() *string {
+				str := "/var/**/*.log"
+				return &str
+			}(),
+		}.Matches("/var/log/app/error.log")
+	}
+}
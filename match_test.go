@@ -1,8 +1,15 @@
 package compare
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestValidation_Matches(t *testing.T) {
@@ -10,6 +17,8 @@ func TestValidation_Matches(t *testing.T) {
 		MatchType     MatchType
 		MatchValue    string
 		ExpectedValue interface{}
+		Normalizers   Normalizers
+		EqualOptions  *EqualOptions
 	}
 	type args struct {
 		value interface{}
@@ -217,9 +226,48 @@ func TestValidation_Matches(t *testing.T) {
 			args: args{
 				value: `{"value":10,"input":"hello","offset":5}`,
 			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "offset in % matchvalue = 10% (single byte changed)",
+			fields: fields{
+				MatchType:     MatchTypePercentageDeviation,
+				MatchValue:    "10%",
+				ExpectedValue: `{"value":5,"input":"hello"}`,
+			},
+			args: args{
+				value: `{"value":6,"input":"hello"}`,
+			},
 			want:    true,
 			wantErr: false,
 		},
+		{
+			name: "offset in % matchvalue = 10% (byte inserted at the start)",
+			fields: fields{
+				MatchType:     MatchTypePercentageDeviation,
+				MatchValue:    "10%",
+				ExpectedValue: "hello world",
+			},
+			args: args{
+				value: "_hello world",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "offset in % matchvalue = 40% (equal length, completely different)",
+			fields: fields{
+				MatchType:     MatchTypePercentageDeviation,
+				MatchValue:    "40%",
+				ExpectedValue: "abcdef",
+			},
+			args: args{
+				value: "uvwxyz",
+			},
+			want:    false,
+			wantErr: false,
+		},
 		{
 			name: "offset in % matchvalue = 10%",
 			fields: fields{
@@ -594,6 +642,298 @@ func TestValidation_Matches(t *testing.T) {
 			want:    true,
 			wantErr: false,
 		},
+		// ============================ Shape
+		{
+			name: "shape (map with additional keys)",
+			fields: fields{
+				MatchType: MatchTypeShape,
+				ExpectedValue: map[string]interface{}{
+					"id":    Validation{MatchType: MatchTypeNotEmpty},
+					"count": Validation{MatchType: MatchTypeGreaterThan, ExpectedValue: int64(0)},
+				},
+			},
+			args: args{
+				value: map[string]interface{}{"id": "a1", "count": int64(3), "extra": true},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "shape (map with missing key)",
+			fields: fields{
+				MatchType:     MatchTypeShape,
+				ExpectedValue: map[string]interface{}{"id": "a1"},
+			},
+			args: args{
+				value: map[string]interface{}{"count": int64(3)},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		// ============================ Equal options
+		{
+			name: "equal with options (ignore slice order)",
+			fields: fields{
+				MatchType:     MatchTypeEqual,
+				ExpectedValue: []string{"a", "b"},
+				EqualOptions:  &EqualOptions{IgnoreSliceOrder: true},
+			},
+			args: args{
+				value: []string{"b", "a"},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "not equal with options (ignore field)",
+			fields: fields{
+				MatchType:     MatchTypeNotEqual,
+				ExpectedValue: map[string]interface{}{"id": 1, "name": "a"},
+				EqualOptions:  &EqualOptions{IgnoreFields: []string{"id"}},
+			},
+			args: args{
+				value: map[string]interface{}{"id": 2, "name": "a"},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		// ============================ Normalizers
+		{
+			name: "equal normalized (string = ' ABC\\r\\n')",
+			fields: fields{
+				MatchType:     MatchTypeEqual,
+				ExpectedValue: "abc\n",
+				Normalizers:   Normalizers{NormalizeLineEndings, NormalizeTrimTrailingSpace, NormalizeTrimSpace, NormalizeCaseFold},
+			},
+			args: args{
+				value: " ABC\r\n",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "equal normalized ([]byte = 'a  b')",
+			fields: fields{
+				MatchType:     MatchTypeEqual,
+				ExpectedValue: []byte("a b"),
+				Normalizers:   Normalizers{NormalizeCollapseSpace},
+			},
+			args: args{
+				value: []byte("a \t b"),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "equal normalized (string and []byte)",
+			fields: fields{
+				MatchType:     MatchTypeEqual,
+				ExpectedValue: "abc",
+				Normalizers:   Normalizers{NormalizeCaseFold},
+			},
+			args: args{
+				value: []byte("abc"),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "not equal normalized (string = '\\x1b[31mabc\\x1b[0m')",
+			fields: fields{
+				MatchType:     MatchTypeNotEqual,
+				ExpectedValue: "abc",
+				Normalizers:   Normalizers{NormalizeStripANSI},
+			},
+			args: args{
+				value: "\x1b[31mabc\x1b[0m",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "contains normalized (string = 'Hello   World')",
+			fields: fields{
+				MatchType:   MatchTypeContains,
+				MatchValue:  "hello world",
+				Normalizers: Normalizers{NormalizeCollapseSpace, NormalizeCaseFold},
+			},
+			args: args{
+				value: "Hello   World",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "contains normalized (int64 = '100')",
+			fields: fields{
+				MatchType:   MatchTypeContains,
+				Normalizers: Normalizers{NormalizeCaseFold},
+			},
+			args: args{
+				value: int64(100),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		// ============================ Starts with
+		{
+			name: "starts with (string = 'api.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeStartsWith,
+				MatchValue: "api.",
+			},
+			args: args{
+				value: "api.example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "starts with (string = 'API.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeStartsWith,
+				MatchValue: "api.",
+			},
+			args: args{
+				value: "API.example.com",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "starts with ignore case (string = 'API.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeStartsWithIgnoreCase,
+				MatchValue: "api.",
+			},
+			args: args{
+				value: "API.example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "starts with ([]byte = 'api.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeStartsWith,
+				MatchValue: "api.",
+			},
+			args: args{
+				value: []byte("api.example.com"),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "starts with (int64 = '1000')",
+			fields: fields{
+				MatchType:  MatchTypeStartsWith,
+				MatchValue: "10",
+			},
+			args: args{
+				value: int64(1000),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "starts with (struct)",
+			fields: fields{
+				MatchType:  MatchTypeStartsWith,
+				MatchValue: "api.",
+			},
+			args: args{
+				value: struct{}{},
+			},
+			want:    false,
+			wantErr: true,
+		},
+		// ============================ Ends with
+		{
+			name: "ends with (string = 'api.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeEndsWith,
+				MatchValue: ".com",
+			},
+			args: args{
+				value: "api.example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "ends with (string = 'api.example.COM')",
+			fields: fields{
+				MatchType:  MatchTypeEndsWith,
+				MatchValue: ".com",
+			},
+			args: args{
+				value: "api.example.COM",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "ends with ignore case (string = 'api.example.COM')",
+			fields: fields{
+				MatchType:  MatchTypeEndsWithIgnoreCase,
+				MatchValue: ".com",
+			},
+			args: args{
+				value: "api.example.COM",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		// ============================ Glob
+		{
+			name: "glob (string = '/var/log/app/error.log')",
+			fields: fields{
+				MatchType:  MatchTypeGlob,
+				MatchValue: "/var/**/*.log",
+			},
+			args: args{
+				value: "/var/log/app/error.log",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "glob (string = '/var/log/app/error.txt')",
+			fields: fields{
+				MatchType:  MatchTypeGlob,
+				MatchValue: "/var/**/*.log",
+			},
+			args: args{
+				value: "/var/log/app/error.txt",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "glob ignore case (string = 'NODE-7.example.com')",
+			fields: fields{
+				MatchType:  MatchTypeGlobIgnoreCase,
+				MatchValue: "node-[0-9].*",
+			},
+			args: args{
+				value: "NODE-7.example.com",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "glob (invalid pattern)",
+			fields: fields{
+				MatchType:  MatchTypeGlob,
+				MatchValue: "node-[0-9",
+			},
+			args: args{
+				value: "node-1",
+			},
+			want:    false,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -601,8 +941,9 @@ func TestValidation_Matches(t *testing.T) {
 				MatchType:     tt.fields.MatchType,
 				MatchValue:    &tt.fields.MatchValue,
 				ExpectedValue: tt.fields.ExpectedValue,
+				EqualOptions:  tt.fields.EqualOptions,
 			}
-			got, err := d.Matches(tt.args.value)
+			got, err := d.MatchesWithOptions(tt.args.value, MatchOptions{Normalizers: tt.fields.Normalizers})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Validation.Matches() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -614,14 +955,33 @@ func TestValidation_Matches(t *testing.T) {
 	}
 }
 
-func Test_valueToInt64(t *testing.T) {
+func TestValidation_Matches_missingMatchValue(t *testing.T) {
+	matchTypes := []MatchType{
+		MatchTypeStartsWith, MatchTypeStartsWithIgnoreCase, MatchTypeEndsWith, MatchTypeEndsWithIgnoreCase,
+		MatchTypeGlob, MatchTypeGlobIgnoreCase,
+	}
+	for _, matchType := range matchTypes {
+		t.Run(string(matchType), func(t *testing.T) {
+			d := Validation{MatchType: matchType}
+			got, err := d.Matches("node-1")
+			if !errors.Is(err, ErrInvalidMatchValue) {
+				t.Errorf("Validation.Matches() error = %v, want %v", err, ErrInvalidMatchValue)
+			}
+			if got {
+				t.Errorf("Validation.Matches() got = %v, want false", got)
+			}
+		})
+	}
+}
+
+func Test_valueToNumber(t *testing.T) {
 	type args struct {
 		value interface{}
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    int64
+		want    number
 		wantErr bool
 	}{
 		{
@@ -629,7 +989,7 @@ func Test_valueToInt64(t *testing.T) {
 			args: args{
 				value: int64(1),
 			},
-			want:    1,
+			want:    number{i: 1},
 			wantErr: false,
 		},
 		{
@@ -637,7 +997,7 @@ func Test_valueToInt64(t *testing.T) {
 			args: args{
 				value: int64(100),
 			},
-			want:    100,
+			want:    number{i: 100},
 			wantErr: false,
 		},
 		{
@@ -645,19 +1005,163 @@ func Test_valueToInt64(t *testing.T) {
 			args: args{
 				value: "echo",
 			},
-			want:    0,
+			want:    number{},
+			wantErr: true,
+		},
+		{
+			name: "float64",
+			args: args{
+				value: 9.99,
+			},
+			want:    number{f: 9.99, isFloat: true},
+			wantErr: false,
+		},
+		{
+			name: "NaN",
+			args: args{
+				value: math.NaN(),
+			},
+			want:    number{},
+			wantErr: true,
+		},
+		{
+			name: "json.Number integer",
+			args: args{
+				value: json.Number("-42"),
+			},
+			want:    number{i: -42},
+			wantErr: false,
+		},
+		{
+			name: "json.Number with fraction",
+			args: args{
+				value: json.Number("1.5"),
+			},
+			want:    number{f: 1.5, isFloat: true},
+			wantErr: false,
+		},
+		{
+			name: "json.Number beyond int64",
+			args: args{
+				value: json.Number("1e19"),
+			},
+			want:    number{f: 1e19, isFloat: true},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueToNumber(tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("valueToNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("valueToNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		a    interface{}
+		b    interface{}
+		want int
+	}{
+		{name: "integers", a: int64(3), b: int64(5), want: -1},
+		{name: "integers beyond float64 precision", a: int64(1<<53 + 1), b: int64(1 << 53), want: 1},
+		{name: "fraction against integer", a: 9.99, b: int64(0), want: 1},
+		{name: "integral float against integer", a: float64(3), b: int64(3), want: 0},
+		{name: "json.Number fraction against integer", a: json.Number("0.5"), b: int64(1), want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareNumbers(tt.a, tt.b)
+			if err != nil || got != tt.want {
+				t.Errorf("compareNumbers() = %v, %v, want %v, nil", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_valueToString(t *testing.T) {
+	type args struct {
+		value interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "string",
+			args: args{
+				value: "abc",
+			},
+			want:    "abc",
+			wantErr: false,
+		},
+		{
+			name: "bytes",
+			args: args{
+				value: []byte("abc"),
+			},
+			want:    "abc",
+			wantErr: false,
+		},
+		{
+			name: "int64",
+			args: args{
+				value: int64(-42),
+			},
+			want:    "-42",
+			wantErr: false,
+		},
+		{
+			name: "bool",
+			args: args{
+				value: true,
+			},
+			want:    "true",
+			wantErr: false,
+		},
+		{
+			name: "stringer",
+			args: args{
+				value: time.Second,
+			},
+			want:    "1s",
+			wantErr: false,
+		},
+		{
+			name: "named string type",
+			args: args{
+				value: MatchTypeGlob,
+			},
+			want:    "gb",
+			wantErr: false,
+		},
+		{
+			name: "struct",
+			args: args{
+				value: struct{ A int }{A: 1},
+			},
+			want:    "",
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := valueToInt64(tt.args.value)
+			got, err := valueToString(tt.args.value)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("valueToInt64() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("valueToString() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("valueToInt64() = %v, want %v", got, tt.want)
+				t.Errorf("valueToString() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -716,3 +1220,97 @@ func Test_rangeParser(t *testing.T) {
 		})
 	}
 }
+
+func TestValidation_encoding(t *testing.T) {
+	matchValue := "10-20"
+	v := Validation{
+		MatchType:     MatchTypeRange,
+		MatchValue:    &matchValue,
+		ExpectedValue: "15",
+		EqualOptions:  &EqualOptions{IgnoreFields: []string{"id"}},
+	}
+
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("gob.Encode() error = %v", err)
+	}
+	fromGob := Validation{}
+	if err := gob.NewDecoder(&buf).Decode(&fromGob); err != nil {
+		t.Fatalf("gob.Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(fromGob, v) {
+		t.Errorf("gob round trip = %+v, want %+v", fromGob, v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	fromJSON := Validation{}
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, v) {
+		t.Errorf("json round trip = %+v, want %+v", fromJSON, v)
+	}
+
+	// Validation must stay comparable
+	w := v
+	if v != w {
+		t.Errorf("Validation is not equal to its copy")
+	}
+}
+
+func TestValidation_Matches_percentageDeviationBound(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func(n int) string {
+		bts := make([]byte, n)
+		for i := range bts {
+			bts[i] = byte('a' + rnd.Intn(26))
+		}
+		return string(bts)
+	}
+	// the unbounded edit script of two dissimilar 200 KB values takes minutes,
+	// the bounded one only depends on the tolerated number of bytes
+	expected, value := random(200000), random(200000)
+	for _, matchValue := range []string{"1%", "100%"} {
+		matchValue := matchValue
+		start := time.Now()
+		got, err := Validation{
+			MatchType:     MatchTypePercentageDeviation,
+			MatchValue:    &matchValue,
+			ExpectedValue: expected,
+		}.Matches(value)
+		if err != nil {
+			t.Fatalf("Validation.Matches() error = %v", err)
+		}
+		if want := matchValue == "100%"; got != want {
+			t.Errorf("Validation.Matches(%s) = %v, want %v", matchValue, got, want)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Validation.Matches(%s) took %v", matchValue, elapsed)
+		}
+	}
+
+	// prepended bytes are insertions, no matter how many edits they take
+	expected = random(10000)
+	for _, prepended := range []int{1001, 1101, 4000} {
+		matchValue := "50%"
+		got, err := Validation{
+			MatchType:     MatchTypePercentageDeviation,
+			MatchValue:    &matchValue,
+			ExpectedValue: expected,
+		}.Matches(random(prepended) + expected)
+		if err != nil || !got {
+			t.Errorf("Validation.Matches(%d bytes prepended) = %v, %v, want true, nil", prepended, got, err)
+		}
+	}
+	matchValue := "10%"
+	if got, err := (Validation{
+		MatchType:     MatchTypePercentageDeviation,
+		MatchValue:    &matchValue,
+		ExpectedValue: expected,
+	}).Matches(random(1101) + expected); err != nil || got {
+		t.Errorf("Validation.Matches(1101 bytes prepended, 10%%) = %v, %v, want false, nil", got, err)
+	}
+}
@@ -0,0 +1,114 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMergePatchNotRepresentable is returned when the difference of two documents cannot be
+	// expressed as a JSON merge patch, because a member must be set to null.
+	ErrMergePatchNotRepresentable = errors.New("difference is not representable as json merge patch")
+)
+
+// CreateMergePatch returns the minimal JSON merge patch (RFC 7386) that transforms
+// the JSON document a into b. Only members that differ are part of the patch.
+//
+// A merge patch has the following limitations:
+//   - null removes a member, so members cannot be set to null. If b contains a null
+//     member that is missing or not null in a, ErrMergePatchNotRepresentable is returned.
+//   - Arrays are replaced wholesale; a changed array is part of the patch in full.
+//   - If a or b is not an object, the patch is b itself and replaces the whole document.
+//
+// If a and b are equal objects, the patch is "{}".
+func CreateMergePatch(a, b []byte) ([]byte, error) {
+	aValue, err := decodeJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	bValue, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := createMergePatch("", aValue, bValue)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(patch)
+}
+
+// createMergePatch returns the patch that transforms a into b located at path.
+func createMergePatch(path string, a, b interface{}) (interface{}, error) {
+	bObject, ok := b.(map[string]interface{})
+	if !ok {
+		return b, nil
+	}
+	aObject, ok := a.(map[string]interface{})
+	if !ok {
+		// the patch is merged into an empty object
+		aObject = map[string]interface{}{}
+	}
+	patch := map[string]interface{}{}
+	for _, key := range sortedJSONKeys(aObject) {
+		if _, ok := bObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for _, key := range sortedJSONKeys(bObject) {
+		memberPath := jsonPointerJoin(path, key)
+		bv := bObject[key]
+		av, exists := aObject[key]
+		if exists && jsonEqual(av, bv) {
+			continue
+		}
+		if bv == nil {
+			return nil, fmt.Errorf("%w: %s must be set to null", ErrMergePatchNotRepresentable, memberPath)
+		}
+		member, err := createMergePatch(memberPath, av, bv)
+		if err != nil {
+			return nil, err
+		}
+		if _, isObject := av.(map[string]interface{}); isObject && exists {
+			if m, ok := member.(map[string]interface{}); ok && len(m) == 0 {
+				continue
+			}
+		}
+		patch[key] = member
+	}
+	return patch, nil
+}
+
+// ApplyMergePatch applies the JSON merge patch (RFC 7386) to the JSON document and returns
+// the patched document. Members of the patch that are null remove the member from the document.
+// A patch that is not an object replaces the whole document.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	patchValue, err := decodeJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(applyMergePatch(target, patchValue))
+}
+
+// applyMergePatch implements the MergePatch function of RFC 7386.
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
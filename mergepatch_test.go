@@ -0,0 +1,161 @@
+package compare
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateMergePatch(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: `{"a":{"b":1}}`,
+				b: `{"a":{"b":1.0}}`,
+			},
+			want:    `{}`,
+			wantErr: nil,
+		},
+		{
+			name: "changed, removed and added members",
+			args: args{
+				a: `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+				b: `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`,
+			},
+			want:    `{"author":{"familyName":null},"phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`,
+			wantErr: nil,
+		},
+		{
+			name: "object replaces scalar",
+			args: args{
+				a: `{"a":"x"}`,
+				b: `{"a":{"b":[null]}}`,
+			},
+			want:    `{"a":{"b":[null]}}`,
+			wantErr: nil,
+		},
+		{
+			name: "non-object root",
+			args: args{
+				a: `{"a":1}`,
+				b: `[1,2]`,
+			},
+			want:    `[1,2]`,
+			wantErr: nil,
+		},
+		{
+			name: "null root",
+			args: args{
+				a: `{"a":1}`,
+				b: `null`,
+			},
+			want:    `null`,
+			wantErr: nil,
+		},
+		{
+			name: "unchanged null member",
+			args: args{
+				a: `{"a":null,"b":1}`,
+				b: `{"a":null,"b":2}`,
+			},
+			want:    `{"b":2}`,
+			wantErr: nil,
+		},
+		{
+			name: "member set to null",
+			args: args{
+				a: `{"a":1}`,
+				b: `{"a":null}`,
+			},
+			want:    ``,
+			wantErr: ErrMergePatchNotRepresentable,
+		},
+		{
+			name: "nested null in new object",
+			args: args{
+				a: `{}`,
+				b: `{"a":{"b":null}}`,
+			},
+			want:    ``,
+			wantErr: ErrMergePatchNotRepresentable,
+		},
+		{
+			name: "invalid json",
+			args: args{
+				a: `{}`,
+				b: `{"a"}`,
+			},
+			want:    ``,
+			wantErr: ErrInvalidJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateMergePatch([]byte(tt.args.a), []byte(tt.args.b))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateMergePatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("CreateMergePatch() = %s, want %s", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			patched, err := ApplyMergePatch([]byte(tt.args.a), got)
+			if err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+			assertJSONEqual(t, patched, tt.args.b)
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// test cases of RFC 7386 appendix A
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"a":1,"e":null}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ApplyMergePatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ApplyMergePatch([]byte(`{}`), []byte(`{`)); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("ApplyMergePatch() error = %v, wantErr %v", err, ErrInvalidJSON)
+	}
+}
@@ -0,0 +1,262 @@
+package compare
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// DefaultMerkleChunkSize defines the size of the chunks hashed into the leaves of a Merkle tree by default.
+const DefaultMerkleChunkSize = 4096
+
+// MerkleChunking defines how data is split into the leaves of a Merkle tree.
+type MerkleChunking int
+
+const (
+	// MerkleChunkingFixed splits data into chunks of MerkleOptions.ChunkSize bytes.
+	// It is suited for data that is changed in place, such as disk images.
+	MerkleChunkingFixed MerkleChunking = iota
+	// MerkleChunkingContentDefined splits data at boundaries chosen by a rolling hash of the content.
+	// Chunks are between a quarter and four times MerkleOptions.ChunkSize bytes long.
+	// Inserted or deleted bytes only change the chunks around them, so the following chunks
+	// keep their hashes.
+	MerkleChunkingContentDefined
+)
+
+// MerkleOptions configures how Merkle trees are built.
+type MerkleOptions struct {
+	// Chunking defines how data is split into leaves.
+	Chunking MerkleChunking
+	// ChunkSize defines the size of fixed chunks or the average size of content-defined chunks.
+	// If 0, DefaultMerkleChunkSize is used.
+	ChunkSize int
+}
+
+// MerkleTree defines a binary hash tree over the chunks of data.
+type MerkleTree struct {
+	root   *merkleNode
+	leaves []*merkleNode
+}
+
+// merkleNode defines a node of a Merkle tree.
+type merkleNode struct {
+	hash []byte
+	// start and end define the byte range covered by the node.
+	start, end int
+	// first and last define the range of leaves covered by the node.
+	first, last int
+	left, right *merkleNode
+}
+
+// MerkleRanges defines the differing regions of two Merkle trees in order of their offsets.
+type MerkleRanges []MerkleRange
+
+// MerkleRange defines a region that differs between A and B.
+// The bytes A[AStart:AEnd] were replaced by B[BStart:BEnd], one of the ranges may be empty.
+type MerkleRange struct {
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+}
+
+// BuildMerkleTree splits data into chunks and builds a Merkle tree over their SHA-256 hashes.
+// Leaves and inner nodes are hashed with different prefixes, so a leaf never collides with an inner node.
+func BuildMerkleTree(data []byte, opts MerkleOptions) *MerkleTree {
+	tree := &MerkleTree{}
+	for i, chunk := range merkleChunks(data, opts) {
+		hash := sha256.Sum256(append([]byte{0x00}, data[chunk[0]:chunk[1]]...))
+		tree.leaves = append(tree.leaves, &merkleNode{hash: hash[:], start: chunk[0], end: chunk[1], first: i, last: i + 1})
+	}
+	level := tree.leaves
+	for len(level) > 1 {
+		next := make([]*merkleNode, 0, (len(level)+1)/2)
+		for i := 0; i+1 < len(level); i += 2 {
+			left, right := level[i], level[i+1]
+			hash := sha256.Sum256(append(append([]byte{0x01}, left.hash...), right.hash...))
+			next = append(next, &merkleNode{hash: hash[:], start: left.start, end: right.end, first: left.first, last: right.last, left: left, right: right})
+		}
+		// an odd node is promoted to the next level unchanged
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	tree.root = level[0]
+	return tree
+}
+
+// Root returns the root hash of the tree. Trees of equal data have equal roots.
+func (t *MerkleTree) Root() []byte {
+	return append([]byte{}, t.root.hash...)
+}
+
+// Len returns the number of bytes covered by the tree.
+func (t *MerkleTree) Len() int {
+	return t.root.end
+}
+
+// CompareMerkleTrees returns the regions that differ between the data of a and b.
+// The trees are descended from the roots, subtrees with equal hashes are skipped.
+// If the subtrees of a differing node cover different numbers of chunks, as it happens
+// after bytes were inserted or deleted, their chunks are aligned by hash instead.
+// The regions are as coarse as the chunks, Reports refines them to single bytes.
+func CompareMerkleTrees(a, b *MerkleTree) MerkleRanges {
+	d := merkleDiff{a: a, b: b, ranges: MerkleRanges{}}
+	d.compare(a.root, b.root)
+	return d.ranges
+}
+
+// MerkleDiff builds Merkle trees over a and b and returns the reports of the differing regions.
+func MerkleDiff(a, b []byte, opts MerkleOptions) Reports {
+	return CompareMerkleTrees(BuildMerkleTree(a, opts), BuildMerkleTree(b, opts)).Reports(a, b)
+}
+
+// Reports diffs the bytes of every region of a and b and returns the reports with indexes
+// relative to a and b. The reports can be applied to a or grouped into hunks like the
+// reports of BytesDiff.
+func (r MerkleRanges) Reports(a, b []byte) Reports {
+	reports := Reports{}
+	for _, rng := range r {
+		for _, report := range BytesDiff(a[rng.AStart:rng.AEnd], b[rng.BStart:rng.BEnd]) {
+			if report.Kind == ReportKindInserted {
+				report.Index += rng.BStart
+			} else {
+				report.Index += rng.AStart
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// merkleDiff collects the differing regions of two trees.
+type merkleDiff struct {
+	a, b   *MerkleTree
+	ranges MerkleRanges
+}
+
+// compare descends into the nodes as long as their hashes differ.
+func (d *merkleDiff) compare(na, nb *merkleNode) {
+	if bytes.Equal(na.hash, nb.hash) {
+		return
+	}
+	if na.left == nil || nb.left == nil || na.last-na.first != nb.last-nb.first {
+		d.align(na, nb)
+		return
+	}
+	d.compare(na.left, nb.left)
+	d.compare(na.right, nb.right)
+}
+
+// align aligns the leaves of the nodes by their hashes and adds the unequal runs.
+func (d *merkleDiff) align(na, nb *merkleNode) {
+	leavesA, leavesB := d.a.leaves[na.first:na.last], d.b.leaves[nb.first:nb.last]
+	script := DiffSequence(len(leavesA), len(leavesB), func(i, j int) bool {
+		return bytes.Equal(leavesA[i].hash, leavesB[j].hash)
+	})
+	for _, edit := range script {
+		if edit.Op == EditEqual {
+			continue
+		}
+		d.add(MerkleRange{
+			AStart: leafOffset(leavesA, edit.AStart, na.end),
+			AEnd:   leafOffset(leavesA, edit.AEnd, na.end),
+			BStart: leafOffset(leavesB, edit.BStart, nb.end),
+			BEnd:   leafOffset(leavesB, edit.BEnd, nb.end),
+		})
+	}
+}
+
+// add adds the range and merges it with the previous range if they are adjacent.
+func (d *merkleDiff) add(rng MerkleRange) {
+	if last := len(d.ranges) - 1; last >= 0 && d.ranges[last].AEnd == rng.AStart && d.ranges[last].BEnd == rng.BStart {
+		d.ranges[last].AEnd, d.ranges[last].BEnd = rng.AEnd, rng.BEnd
+		return
+	}
+	d.ranges = append(d.ranges, rng)
+}
+
+// leafOffset returns the offset of the i-th leaf or end if i is behind the last leaf.
+func leafOffset(leaves []*merkleNode, i, end int) int {
+	if i < len(leaves) {
+		return leaves[i].start
+	}
+	return end
+}
+
+// merkleChunks returns the [start, end) offsets of the chunks of data.
+// Empty data results in a single empty chunk.
+func merkleChunks(data []byte, opts MerkleOptions) [][2]int {
+	size := opts.ChunkSize
+	if size <= 0 {
+		size = DefaultMerkleChunkSize
+	}
+	if len(data) == 0 {
+		return [][2]int{{0, 0}}
+	}
+	if opts.Chunking == MerkleChunkingContentDefined {
+		return contentDefinedChunks(data, size)
+	}
+	chunks := [][2]int{}
+	for start := 0; start < len(data); start += size {
+		end := start + size
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// contentDefinedChunks splits data with a gear rolling hash. A chunk ends where the masked bits
+// of the hash are zero, so boundaries depend on the preceding bytes instead of their offset.
+func contentDefinedChunks(data []byte, size int) [][2]int {
+	minSize, maxSize := size/4, size*4
+	if minSize < 1 {
+		minSize = 1
+	}
+	// the high bits of the gear hash depend on the most bytes, so the mask selects the
+	// high log2(size) bits and a boundary follows on average every size bytes
+	bits := uint(0)
+	for 1<<bits < size {
+		bits++
+	}
+	mask := ^uint64(0) << (64 - bits)
+	if bits == 0 {
+		mask = 0
+	}
+
+	chunks := [][2]int{}
+	start := 0
+	hash := uint64(0)
+	for i, b := range data {
+		hash = hash<<1 + gearTable[b]
+		length := i + 1 - start
+		if length < minSize {
+			continue
+		}
+		if hash&mask == 0 || length >= maxSize {
+			chunks = append(chunks, [2]int{start, i + 1})
+			start, hash = i+1, 0
+		}
+	}
+	if start < len(data) {
+		chunks = append(chunks, [2]int{start, len(data)})
+	}
+	return chunks
+}
+
+// gearTable maps every byte to a pseudo random value for the gear rolling hash.
+// The values are generated by splitmix64, so chunk boundaries are stable across builds.
+var gearTable = func() [256]uint64 {
+	table := [256]uint64{}
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		table[i] = z ^ z>>31
+	}
+	return table
+}()
@@ -0,0 +1,163 @@
+package compare
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCompareMerkleTrees(t *testing.T) {
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	changed := append([]byte{}, data...)
+	changed[20] = 0xff
+	changed[21] = 0xff
+	changed[60] = 0xff
+	inserted := append(append(append([]byte{}, data[:32]...), 0xaa, 0xbb), data[32:]...)
+
+	type args struct {
+		a    []byte
+		b    []byte
+		opts MerkleOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want MerkleRanges
+	}{
+		{
+			name: "equal",
+			args: args{a: data, b: data, opts: MerkleOptions{ChunkSize: 8}},
+			want: MerkleRanges{},
+		},
+		{
+			name: "changed chunks",
+			args: args{a: data, b: changed, opts: MerkleOptions{ChunkSize: 8}},
+			want: MerkleRanges{
+				{AStart: 16, AEnd: 24, BStart: 16, BEnd: 24},
+				{AStart: 56, AEnd: 64, BStart: 56, BEnd: 64},
+			},
+		},
+		{
+			name: "appended data",
+			args: args{a: data[:40], b: data, opts: MerkleOptions{ChunkSize: 8}},
+			want: MerkleRanges{
+				{AStart: 40, AEnd: 40, BStart: 40, BEnd: 64},
+			},
+		},
+		{
+			name: "empty and data",
+			args: args{a: []byte{}, b: data[:10], opts: MerkleOptions{ChunkSize: 8}},
+			want: MerkleRanges{
+				{AStart: 0, AEnd: 0, BStart: 0, BEnd: 10},
+			},
+		},
+		{
+			name: "inserted bytes shift fixed chunks",
+			args: args{a: data, b: inserted, opts: MerkleOptions{ChunkSize: 8}},
+			want: MerkleRanges{
+				{AStart: 32, AEnd: 64, BStart: 32, BEnd: 66},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := BuildMerkleTree(tt.args.a, tt.args.opts), BuildMerkleTree(tt.args.b, tt.args.opts)
+			if got := CompareMerkleTrees(a, b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompareMerkleTrees() = %v, want %v", got, tt.want)
+			}
+			if rootsEqual := bytes.Equal(a.Root(), b.Root()); rootsEqual != (len(tt.want) == 0) {
+				t.Errorf("Root() equal = %v, want %v", rootsEqual, len(tt.want) == 0)
+			}
+			if a.Len() != len(tt.args.a) {
+				t.Errorf("Len() = %d, want %d", a.Len(), len(tt.args.a))
+			}
+		})
+	}
+}
+
+func TestCompareMerkleTrees_contentDefined(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	a := make([]byte, 1<<20)
+	random.Read(a)
+	b := append(append(append([]byte{}, a[:300000]...), []byte("inserted")...), a[300000:]...)
+	copy(b[800000:], "changed")
+
+	opts := MerkleOptions{Chunking: MerkleChunkingContentDefined, ChunkSize: 1024}
+	ranges := CompareMerkleTrees(BuildMerkleTree(a, opts), BuildMerkleTree(b, opts))
+	if len(ranges) != 2 {
+		t.Fatalf("CompareMerkleTrees() = %v, want 2 ranges", ranges)
+	}
+	for _, rng := range ranges {
+		if rng.AEnd-rng.AStart > 3*4*opts.ChunkSize {
+			t.Errorf("CompareMerkleTrees() range %v is larger than the chunks around the change", rng)
+		}
+	}
+	if got, err := Apply(a, ranges.Reports(a, b)); err != nil || !bytes.Equal(got, b) {
+		t.Errorf("Apply() error = %v, equal = %v", err, bytes.Equal(got, b))
+	}
+}
+
+func TestMerkleDiff(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := make([]byte, random.Intn(200))
+		random.Read(a)
+		b := append([]byte{}, a...)
+		for k := random.Intn(4); k > 0; k-- {
+			pos := random.Intn(len(b) + 1)
+			switch random.Intn(3) {
+			case 0:
+				b = append(b[:pos], append([]byte{byte(random.Intn(256))}, b[pos:]...)...)
+			case 1:
+				if pos < len(b) {
+					b = append(b[:pos], b[pos+1:]...)
+				}
+			default:
+				if pos < len(b) {
+					b[pos]++
+				}
+			}
+		}
+		opts := MerkleOptions{Chunking: MerkleChunking(random.Intn(2)), ChunkSize: 1 + random.Intn(16)}
+		reports := MerkleDiff(a, b, opts)
+		got, err := Apply(a, reports)
+		if err != nil {
+			t.Fatalf("Apply(MerkleDiff(%v, %v, %+v)) error = %v", a, b, opts, err)
+		}
+		if !bytes.Equal(got, b) {
+			t.Fatalf("Apply(MerkleDiff(%v, %v, %+v)) = %v", a, b, opts, got)
+		}
+		if _, err := reports.Hunks(a, DefaultContextLines); err != nil {
+			t.Fatalf("Reports.Hunks() error = %v", err)
+		}
+	}
+}
+
+func TestContentDefinedChunks(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	data := make([]byte, 100000)
+	random.Read(data)
+	size := 256
+	chunks := contentDefinedChunks(data, size)
+	end := 0
+	for i, chunk := range chunks {
+		if chunk[0] != end {
+			t.Fatalf("chunk %d starts at %d, want %d", i, chunk[0], end)
+		}
+		length := chunk[1] - chunk[0]
+		if length > 4*size || (length < size/4 && i != len(chunks)-1) {
+			t.Errorf("chunk %d has length %d", i, length)
+		}
+		end = chunk[1]
+	}
+	if end != len(data) {
+		t.Errorf("chunks end at %d, want %d", end, len(data))
+	}
+	if average := len(data) / len(chunks); average < size/2 || average > 2*size {
+		t.Errorf("average chunk length = %d, want about %d", average, size)
+	}
+}
@@ -0,0 +1,217 @@
+package compare
+
+// EditOp defines the operation of an edit.
+type EditOp int
+
+const (
+	// EditEqual is used if the elements exist in A and B.
+	EditEqual EditOp = iota
+	// EditInsert is used if the elements only exist in B.
+	EditInsert
+	// EditDelete is used if the elements only exist in A.
+	EditDelete
+)
+
+// String returns the name of the edit operation.
+func (op EditOp) String() string {
+	switch op {
+	case EditEqual:
+		return "equal"
+	case EditInsert:
+		return "insert"
+	case EditDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Edit describes a run of equal, inserted or deleted elements.
+// The elements A[AStart:AEnd] and B[BStart:BEnd] are affected by the edit.
+// Inserts have an empty range in A and deletes have an empty range in B.
+type Edit struct {
+	Op     EditOp
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+}
+
+// EditScript defines an ordered list of edits that transforms A into B.
+// Between two equal runs, deleted elements are always listed before inserted elements.
+type EditScript []Edit
+
+// myersDiff returns the shortest edit script that transforms a sequence of length n into a
+// sequence of length m. equal reports whether the i-th element of A equals the j-th element of B.
+// The implementation uses the O(ND) algorithm by Eugene W. Myers with the linear space refinement.
+func myersDiff(n, m int, equal func(i, j int) bool) EditScript {
+	size := 2*(n+m) + 4
+	d := &myers{
+		equal:   equal,
+		forward: make([]int, size),
+		reverse: make([]int, size),
+		script:  EditScript{},
+	}
+	d.compare(0, n, 0, m)
+	return d.script.canonical()
+}
+
+// editDistanceWithin returns the number of inserted and deleted elements of the shortest
+// edit script of sequences of length n and m, if it does not exceed max.
+// Unlike myersDiff, the cost is bounded: it takes O((n+m)*max) time and O(max) space.
+func editDistanceWithin(n, m int, equal func(i, j int) bool, max int) (int, bool) {
+	if max > n+m {
+		max = n + m
+	}
+	if max < 0 {
+		return 0, false
+	}
+	offset := max + 1
+	// v holds the furthest x of every diagonal k = x - y
+	v := make([]int, 2*max+3)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && equal(x, y) {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// canonical merges every run of inserts and deletes between two equal runs into
+// a single delete followed by a single insert.
+func (s EditScript) canonical() EditScript {
+	result := make(EditScript, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i].Op == EditEqual {
+			result = append(result, s[i])
+			continue
+		}
+		run := s[i]
+		for i+1 < len(s) && s[i+1].Op != EditEqual {
+			i++
+			run.AEnd, run.BEnd = s[i].AEnd, s[i].BEnd
+		}
+		if run.AStart < run.AEnd {
+			result = append(result, Edit{Op: EditDelete, AStart: run.AStart, AEnd: run.AEnd, BStart: run.BStart, BEnd: run.BStart})
+		}
+		if run.BStart < run.BEnd {
+			result = append(result, Edit{Op: EditInsert, AStart: run.AEnd, AEnd: run.AEnd, BStart: run.BStart, BEnd: run.BEnd})
+		}
+	}
+	return result
+}
+
+// myers holds the state of a single diff computation.
+type myers struct {
+	equal   func(i, j int) bool
+	forward []int
+	reverse []int
+	script  EditScript
+}
+
+// add appends the edit to the script and merges it with the previous edit if possible.
+func (d *myers) add(op EditOp, aStart, aEnd, bStart, bEnd int) {
+	if aStart == aEnd && bStart == bEnd {
+		return
+	}
+	if last := len(d.script) - 1; last >= 0 && d.script[last].Op == op && d.script[last].AEnd == aStart && d.script[last].BEnd == bStart {
+		d.script[last].AEnd = aEnd
+		d.script[last].BEnd = bEnd
+		return
+	}
+	d.script = append(d.script, Edit{Op: op, AStart: aStart, AEnd: aEnd, BStart: bStart, BEnd: bEnd})
+}
+
+// compare computes the edit script of A[aLo:aHi] and B[bLo:bHi].
+func (d *myers) compare(aLo, aHi, bLo, bHi int) {
+	prefix := 0
+	for aLo+prefix < aHi && bLo+prefix < bHi && d.equal(aLo+prefix, bLo+prefix) {
+		prefix++
+	}
+	d.add(EditEqual, aLo, aLo+prefix, bLo, bLo+prefix)
+	aLo, bLo = aLo+prefix, bLo+prefix
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.equal(aHi-suffix-1, bHi-suffix-1) {
+		suffix++
+	}
+	aEnd, bEnd := aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aEnd:
+		d.add(EditInsert, aLo, aLo, bLo, bEnd)
+	case bLo == bEnd:
+		d.add(EditDelete, aLo, aEnd, bLo, bLo)
+	default:
+		x, y, u, v := d.middleSnake(aLo, aEnd, bLo, bEnd)
+		d.compare(aLo, x, bLo, y)
+		d.add(EditEqual, x, u, y, v)
+		d.compare(u, aEnd, v, bEnd)
+	}
+
+	d.add(EditEqual, aEnd, aHi, bEnd, bHi)
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of the
+// shortest edit script of A[aLo:aHi] and B[bLo:bHi]. Both ranges must not be empty.
+func (d *myers) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	offset := max + 1
+	d.forward[offset+1] = 0
+	d.reverse[offset+1] = 0
+
+	for step := 0; step <= max; step++ {
+		for k := -step; k <= step; k += 2 {
+			var fx int
+			if k == -step || (k != step && d.forward[offset+k-1] < d.forward[offset+k+1]) {
+				fx = d.forward[offset+k+1]
+			} else {
+				fx = d.forward[offset+k-1] + 1
+			}
+			fy := fx - k
+			sx, sy := fx, fy
+			for fx < n && fy < m && d.equal(aLo+fx, bLo+fy) {
+				fx++
+				fy++
+			}
+			d.forward[offset+k] = fx
+			if c := delta - k; odd && c >= -(step-1) && c <= step-1 && fx+d.reverse[offset+c] >= n {
+				return aLo + sx, bLo + sy, aLo + fx, bLo + fy
+			}
+		}
+		for k := -step; k <= step; k += 2 {
+			var rx int
+			if k == -step || (k != step && d.reverse[offset+k-1] < d.reverse[offset+k+1]) {
+				rx = d.reverse[offset+k+1]
+			} else {
+				rx = d.reverse[offset+k-1] + 1
+			}
+			ry := rx - k
+			sx, sy := rx, ry
+			for rx < n && ry < m && d.equal(aHi-rx-1, bHi-ry-1) {
+				rx++
+				ry++
+			}
+			d.reverse[offset+k] = rx
+			if c := delta - k; !odd && c >= -step && c <= step && rx+d.forward[offset+c] >= n {
+				return aHi - rx, bHi - ry, aHi - sx, bHi - sy
+			}
+		}
+	}
+	// unreachable: the paths always overlap within max steps
+	return aLo, bLo, aLo, bLo
+}
@@ -0,0 +1,211 @@
+package compare
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// lcsLength returns the length of the longest common subsequence of a and b.
+func lcsLength(a, b []byte) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] > cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// applyEditScript reconstructs b from a and the edit script.
+func applyEditScript(t *testing.T, a, b []byte, script EditScript) []byte {
+	result := []byte{}
+	aPos, bPos := 0, 0
+	for _, edit := range script {
+		if edit.AStart != aPos || edit.BStart != bPos {
+			t.Fatalf("edit %+v does not continue at a=%d, b=%d", edit, aPos, bPos)
+		}
+		switch edit.Op {
+		case EditEqual:
+			if !reflect.DeepEqual(a[edit.AStart:edit.AEnd], b[edit.BStart:edit.BEnd]) {
+				t.Fatalf("equal edit %+v covers different bytes", edit)
+			}
+			result = append(result, a[edit.AStart:edit.AEnd]...)
+		case EditInsert:
+			result = append(result, b[edit.BStart:edit.BEnd]...)
+		}
+		aPos, bPos = edit.AEnd, edit.BEnd
+	}
+	if aPos != len(a) || bPos != len(b) {
+		t.Fatalf("edit script ends at a=%d, b=%d, want a=%d, b=%d", aPos, bPos, len(a), len(b))
+	}
+	return result
+}
+
+func TestBytesEditScript(t *testing.T) {
+	type args struct {
+		a []byte
+		b []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want EditScript
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: []byte("abc"),
+				b: []byte("abc"),
+			},
+			want: EditScript{
+				{Op: EditEqual, AStart: 0, AEnd: 3, BStart: 0, BEnd: 3},
+			},
+		},
+		{
+			name: "both empty",
+			args: args{
+				a: []byte{},
+				b: []byte{},
+			},
+			want: EditScript{},
+		},
+		{
+			name: "insert at start",
+			args: args{
+				a: []byte("abc"),
+				b: []byte("xabc"),
+			},
+			want: EditScript{
+				{Op: EditInsert, AStart: 0, AEnd: 0, BStart: 0, BEnd: 1},
+				{Op: EditEqual, AStart: 0, AEnd: 3, BStart: 1, BEnd: 4},
+			},
+		},
+		{
+			name: "delete in the middle",
+			args: args{
+				a: []byte("abxc"),
+				b: []byte("abc"),
+			},
+			want: EditScript{
+				{Op: EditEqual, AStart: 0, AEnd: 2, BStart: 0, BEnd: 2},
+				{Op: EditDelete, AStart: 2, AEnd: 3, BStart: 2, BEnd: 2},
+				{Op: EditEqual, AStart: 3, AEnd: 4, BStart: 2, BEnd: 3},
+			},
+		},
+		{
+			name: "replace",
+			args: args{
+				a: []byte("abc"),
+				b: []byte("xyz"),
+			},
+			want: EditScript{
+				{Op: EditDelete, AStart: 0, AEnd: 3, BStart: 0, BEnd: 0},
+				{Op: EditInsert, AStart: 3, AEnd: 3, BStart: 0, BEnd: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BytesEditScript(tt.args.a, tt.args.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BytesEditScript() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytesEditScript_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	random := func(n int) []byte {
+		bts := make([]byte, n)
+		for i := range bts {
+			// a small alphabet produces many common subsequences
+			bts[i] = byte('a' + rnd.Intn(4))
+		}
+		return bts
+	}
+	for i := 0; i < 500; i++ {
+		a := random(rnd.Intn(40))
+		b := random(rnd.Intn(40))
+		script := BytesEditScript(a, b)
+		if got := applyEditScript(t, a, b, script); !reflect.DeepEqual(got, b) && !(len(got) == 0 && len(b) == 0) {
+			t.Fatalf("edit script of %q and %q reconstructs %q", a, b, got)
+		}
+		edits := 0
+		for _, edit := range script {
+			if edit.Op != EditEqual {
+				edits += (edit.AEnd - edit.AStart) + (edit.BEnd - edit.BStart)
+			}
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("edit script of %q and %q has %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+func Test_editDistanceWithin(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	random := func(n int) []byte {
+		bts := make([]byte, n)
+		for i := range bts {
+			bts[i] = byte('a' + rnd.Intn(4))
+		}
+		return bts
+	}
+	for i := 0; i < 500; i++ {
+		a := random(rnd.Intn(40))
+		b := random(rnd.Intn(40))
+		want := len(a) + len(b) - 2*lcsLength(a, b)
+		max := rnd.Intn(80)
+		got, ok := editDistanceWithin(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }, max)
+		if ok != (want <= max) || (ok && got != want) {
+			t.Fatalf("editDistanceWithin(%q, %q, %d) = %d, %v, want %d", a, b, max, got, ok, want)
+		}
+	}
+}
+
+func TestEditOp_String(t *testing.T) {
+	tests := []struct {
+		name string
+		op   EditOp
+		want string
+	}{
+		{
+			name: "equal",
+			op:   EditEqual,
+			want: "equal",
+		},
+		{
+			name: "insert",
+			op:   EditInsert,
+			want: "insert",
+		},
+		{
+			name: "delete",
+			op:   EditDelete,
+			want: "delete",
+		},
+		{
+			name: "unknown",
+			op:   EditOp(42),
+			want: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op.String(); got != tt.want {
+				t.Errorf("EditOp.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package compare
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalizer transforms a string before it is compared.
+type Normalizer func(string) string
+
+// Normalizers defines an ordered pipeline of normalizers.
+// The first normalizer receives the input, every following normalizer receives
+// the output of its predecessor.
+type Normalizers []Normalizer
+
+// Normalize runs all normalizers in order and returns the result.
+func (n Normalizers) Normalize(s string) string {
+	for _, normalize := range n {
+		s = normalize(s)
+	}
+	return s
+}
+
+// ComposeNormalizers returns a normalizer that runs the given normalizers in order.
+// The result can itself be part of another pipeline.
+func ComposeNormalizers(normalizers ...Normalizer) Normalizer {
+	return Normalizers(normalizers).Normalize
+}
+
+var (
+	// NormalizeCaseFold maps every character to a canonical member of its case folding orbit
+	// (unicode.SimpleFold), so "ΟΔΟΣ", "οδος" and "οδοσ" normalize to the same string.
+	// The canonical character is the lower case one where possible, e.g. ASCII input is lowercased.
+	NormalizeCaseFold Normalizer = caseFold
+	// NormalizeTrimSpace removes leading and trailing whitespace of the input.
+	NormalizeTrimSpace Normalizer = strings.TrimSpace
+	// NormalizeTrimTrailingSpace removes trailing whitespace of every line of the input.
+	NormalizeTrimTrailingSpace Normalizer = trimTrailingSpace
+	// NormalizeCollapseSpace replaces every run of whitespace, except line breaks, with a single space.
+	NormalizeCollapseSpace Normalizer = collapseSpace
+	// NormalizeLineEndings converts CRLF line endings to LF.
+	NormalizeLineEndings Normalizer = normalizeLineEndings
+	// NormalizeStripANSI removes ANSI escape sequences (colors, cursor movement, hyperlinks) from the input.
+	NormalizeStripANSI Normalizer = stripANSI
+)
+
+// ansiEscapeRegex matches CSI sequences, OSC sequences terminated by BEL or ST and
+// two-character escape sequences.
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// caseFold maps every rune of s to its canonical case folding, see foldRune.
+func caseFold(s string) string {
+	return strings.Map(foldRune, s)
+}
+
+// foldRune returns the same rune for all runes that are equal under simple case folding:
+// the lower case form of the smallest rune of the folding orbit, or the smallest rune itself
+// if its lower case form is not part of the orbit.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return r
+	}
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	lower := unicode.ToLower(min)
+	for f := unicode.SimpleFold(min); f != min; f = unicode.SimpleFold(f) {
+		if f == lower {
+			return lower
+		}
+	}
+	return min
+}
+
+// trimTrailingSpace removes trailing whitespace of every line.
+func trimTrailingSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimRightFunc(line, unicode.IsSpace)
+		// keep the carriage return of CRLF line endings
+		if strings.HasSuffix(line, "\r") && i < len(lines)-1 {
+			trimmed += "\r"
+		}
+		lines[i] = trimmed
+	}
+	return strings.Join(lines, "\n")
+}
+
+// collapseSpace replaces every run of whitespace, except line breaks, with a single space.
+func collapseSpace(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	inSpace := false
+	for _, r := range s {
+		if r != '\n' && r != '\r' && unicode.IsSpace(r) {
+			if !inSpace {
+				sb.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// normalizeLineEndings converts CRLF line endings to LF.
+func normalizeLineEndings(s string) string {
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
+
+// stripANSI removes ANSI escape sequences.
+func stripANSI(s string) string {
+	return ansiEscapeRegex.ReplaceAllString(s, "")
+}
+
+// normalizableString returns the value as string if it is a string, a named
+// string type or a byte slice.
+func normalizableString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String(), true
+	}
+	return "", false
+}
+
+// NormalizedBytesDifferent normalizes a and b and returns the difference between the results.
+func NormalizedBytesDifferent(a, b []byte, normalizers Normalizers) (Reports, error) {
+	return BytesDifferent([]byte(normalizers.Normalize(string(a))), []byte(normalizers.Normalize(string(b))))
+}
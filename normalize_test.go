@@ -0,0 +1,150 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizers_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		normalizers Normalizers
+		in          string
+		want        string
+	}{
+		{
+			name:        "no normalizers",
+			normalizers: nil,
+			in:          " Hello\r\n",
+			want:        " Hello\r\n",
+		},
+		{
+			name:        "case fold",
+			normalizers: Normalizers{NormalizeCaseFold},
+			in:          "HeLLo",
+			want:        "hello",
+		},
+		{
+			name:        "case fold greek final sigma",
+			normalizers: Normalizers{NormalizeCaseFold},
+			in:          "ΟΔΟΣ οδος οδοσ",
+			want:        "οδοσ οδοσ οδοσ",
+		},
+		{
+			name:        "case fold kelvin sign and long s",
+			normalizers: Normalizers{NormalizeCaseFold},
+			in:          "\u212Aſ",
+			want:        "ks",
+		},
+		{
+			name:        "case fold keeps runes without simple folding",
+			normalizers: Normalizers{NormalizeCaseFold},
+			in:          "İß",
+			want:        "İß",
+		},
+		{
+			name:        "trim space",
+			normalizers: Normalizers{NormalizeTrimSpace},
+			in:          " \thello\n ",
+			want:        "hello",
+		},
+		{
+			name:        "trim trailing space",
+			normalizers: Normalizers{NormalizeTrimTrailingSpace},
+			in:          " a \t\n b  \r\n c ",
+			want:        " a\n b\r\n c",
+		},
+		{
+			name:        "collapse space",
+			normalizers: Normalizers{NormalizeCollapseSpace},
+			in:          "a  \t b\n\nc   d",
+			want:        "a b\n\nc d",
+		},
+		{
+			name:        "line endings",
+			normalizers: Normalizers{NormalizeLineEndings},
+			in:          "a\r\nb\rc\n",
+			want:        "a\nb\rc\n",
+		},
+		{
+			name:        "strip ansi",
+			normalizers: Normalizers{NormalizeStripANSI},
+			in:          "\x1b[1;31merror\x1b[0m: \x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\",
+			want:        "error: link",
+		},
+		{
+			name:        "pipeline runs in order",
+			normalizers: Normalizers{NormalizeLineEndings, NormalizeTrimTrailingSpace, NormalizeCollapseSpace, NormalizeCaseFold},
+			in:          "Hello   World  \r\nFOO\r\n",
+			want:        "hello world\nfoo\n",
+		},
+		{
+			name:        "composed pipeline",
+			normalizers: Normalizers{ComposeNormalizers(NormalizeStripANSI, NormalizeTrimSpace), NormalizeCaseFold},
+			in:          " \x1b[32mOK\x1b[0m ",
+			want:        "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.normalizers.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalizers.Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedBytesDifferent(t *testing.T) {
+	type args struct {
+		a           []byte
+		b           []byte
+		normalizers Normalizers
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Reports
+		wantErr bool
+	}{
+		{
+			name: "equal after normalization",
+			args: args{
+				a:           []byte("Hello\r\n"),
+				b:           []byte("hello\n"),
+				normalizers: Normalizers{NormalizeLineEndings, NormalizeCaseFold},
+			},
+			want:    Reports{},
+			wantErr: false,
+		},
+		{
+			name: "different after normalization",
+			args: args{
+				a:           []byte("Hello!"),
+				b:           []byte("hello"),
+				normalizers: Normalizers{NormalizeCaseFold},
+			},
+			want: Reports{
+				{
+					Type:     "uint8",
+					Kind:     ReportKindDeleted,
+					Index:    5,
+					Original: func() *byte { b := byte('!'); return &b }(),
+					New:      0,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizedBytesDifferent(tt.args.a, tt.args.b, tt.args.normalizers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizedBytesDifferent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizedBytesDifferent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
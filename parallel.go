@@ -0,0 +1,112 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// DefaultParallelChunkSize defines the number of bytes a worker compares at once by default.
+const DefaultParallelChunkSize = 256 * 1024
+
+// ParallelOptions configures the parallel comparison.
+type ParallelOptions struct {
+	// Workers defines the number of goroutines comparing chunks.
+	// If 0, runtime.GOMAXPROCS(0) is used.
+	Workers int
+	// ChunkSize defines the number of bytes a worker compares at once.
+	// If 0, DefaultParallelChunkSize is used.
+	ChunkSize int
+}
+
+// BytesDifferentParallel returns the same reports as BytesDifferent, but splits a and b into chunks
+// that are compared concurrently. The reports of the chunks are merged in order of their index.
+// If ctx is canceled before the comparison is complete, the error of ctx is returned.
+func BytesDifferentParallel(ctx context.Context, a, b []byte, opts ParallelOptions) (Reports, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	size := opts.ChunkSize
+	if size <= 0 {
+		size = DefaultParallelChunkSize
+	}
+	common := len(a)
+	if len(b) < common {
+		common = len(b)
+	}
+	typ := reflect.TypeOf(byte(0)).String()
+
+	chunks := make([]Reports, (common+size-1)/size)
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				start := chunk * size
+				end := start + size
+				if end > common {
+					end = common
+				}
+				chunks[chunk] = changedBytes(a[start:end], b[start:end], start, typ)
+			}
+		}()
+	}
+	func() {
+		defer close(jobs)
+		for chunk := range chunks {
+			select {
+			case jobs <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reports := Reports{}
+	for _, chunk := range chunks {
+		reports = append(reports, chunk...)
+	}
+	for idx := common; idx < len(b); idx++ {
+		reports = append(reports, Report{Type: typ, Kind: ReportKindInserted, Index: idx, New: b[idx]})
+	}
+	for idx := common; idx < len(a); idx++ {
+		orig := a[idx]
+		reports = append(reports, Report{Type: typ, Kind: ReportKindDeleted, Index: idx, Original: &orig})
+	}
+	return reports, nil
+}
+
+// changedBlockSize defines the number of bytes changedBytes skips at once if they are equal.
+const changedBlockSize = 4096
+
+// changedBytes reports every index of the equal-length chunks a and b with different values
+// as changed. offset is the index of the first byte of the chunks. Equal blocks are skipped
+// by bytes.Equal, only differing blocks are compared byte by byte.
+func changedBytes(a, b []byte, offset int, typ string) Reports {
+	var reports Reports
+	for start := 0; start < len(a); start += changedBlockSize {
+		end := start + changedBlockSize
+		if end > len(a) {
+			end = len(a)
+		}
+		if bytes.Equal(a[start:end], b[start:end]) {
+			continue
+		}
+		for idx := start; idx < end; idx++ {
+			if a[idx] != b[idx] {
+				orig := a[idx]
+				reports = append(reports, Report{Type: typ, Kind: ReportKindChanged, Index: offset + idx, Original: &orig, New: b[idx]})
+			}
+		}
+	}
+	return reports
+}
@@ -0,0 +1,47 @@
+package compare
+
+import (
+	"context"
+	"testing"
+)
+
+// parallelBenchData returns two 64 MiB buffers that differ in every 1 MiB.
+func parallelBenchData() ([]byte, []byte) {
+	a := make([]byte, 64<<20)
+	b := make([]byte, 64<<20)
+	for i := range a {
+		a[i] = byte(i)
+		b[i] = byte(i)
+	}
+	for i := 0; i < len(b); i += 1 << 20 {
+		b[i]++
+	}
+	return a, b
+}
+
+func BenchmarkBytesDifferent(b *testing.B) {
+	x, y := parallelBenchData()
+	b.SetBytes(int64(len(x)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BytesDifferent(x, y)
+	}
+}
+
+func BenchmarkBytesDifferentParallel(b *testing.B) {
+	x, y := parallelBenchData()
+	b.SetBytes(int64(len(x)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BytesDifferentParallel(context.Background(), x, y, ParallelOptions{})
+	}
+}
+
+func BenchmarkBytesDifferentParallel_SingleWorker(b *testing.B) {
+	x, y := parallelBenchData()
+	b.SetBytes(int64(len(x)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BytesDifferentParallel(context.Background(), x, y, ParallelOptions{Workers: 1})
+	}
+}
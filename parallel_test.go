@@ -0,0 +1,64 @@
+package compare
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBytesDifferentParallel(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := make([]byte, random.Intn(100))
+		random.Read(a)
+		b := append([]byte{}, a[:random.Intn(len(a)+1)]...)
+		for k := random.Intn(6); k > 0 && len(b) > 0; k-- {
+			b[random.Intn(len(b))] = byte(random.Intn(256))
+		}
+		tail := make([]byte, random.Intn(10))
+		random.Read(tail)
+		b = append(b, tail...)
+
+		want, err := BytesDifferent(a, b)
+		if err != nil {
+			t.Fatalf("BytesDifferent() error = %v", err)
+		}
+		opts := ParallelOptions{Workers: 1 + random.Intn(4), ChunkSize: 1 + random.Intn(16)}
+		got, err := BytesDifferentParallel(context.Background(), a, b, opts)
+		if err != nil {
+			t.Fatalf("BytesDifferentParallel() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("BytesDifferentParallel(%v, %v, %+v) = %v, want %v", a, b, opts, got, want)
+		}
+	}
+}
+
+func TestBytesDifferentParallel_defaults(t *testing.T) {
+	a := make([]byte, 3*DefaultParallelChunkSize)
+	b := make([]byte, 3*DefaultParallelChunkSize)
+	// differences in the first and the last block of a chunk and a partial last block
+	indexes := []int{DefaultParallelChunkSize + 1, 2*DefaultParallelChunkSize - 1, len(a) - 1}
+	for _, idx := range indexes {
+		b[idx] = 1
+	}
+	got, err := BytesDifferentParallel(context.Background(), a, append(b, 7), ParallelOptions{})
+	if err != nil {
+		t.Fatalf("BytesDifferentParallel() error = %v", err)
+	}
+	want, _ := BytesDifferent(a, append(b, 7))
+	if !reflect.DeepEqual(got, want) || len(got) != len(indexes)+1 {
+		t.Errorf("BytesDifferentParallel() = %v, want %v", got, want)
+	}
+}
+
+func TestBytesDifferentParallel_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := BytesDifferentParallel(ctx, []byte("abcd"), []byte("abce"), ParallelOptions{ChunkSize: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("BytesDifferentParallel() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -80,3 +80,18 @@ func NewPercentFromFloats(a, b int) (*Percent, error) {
 
 	return NewPercent(p)
 }
+
+// newDeviationPercent returns the share of different elements in total elements.
+// The result is capped at 100%, e.g. if more elements were inserted than originally existed.
+func newDeviationPercent(total, different int) (*Percent, error) {
+	if total == 0 {
+		if different == 0 {
+			return NewPercent(0.0)
+		}
+		return NewPercent(100.0)
+	}
+	if different >= total {
+		return NewPercent(100.0)
+	}
+	return NewPercent(float64(different) / float64(total) * 100)
+}
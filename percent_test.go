@@ -551,3 +551,95 @@ func TestNewPercentFromFloats(t *testing.T) {
 		})
 	}
 }
+
+func Test_newDeviationPercent(t *testing.T) {
+	type args struct {
+		total     int
+		different int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Percent
+		wantErr bool
+	}{
+		{
+			name: "nothing different",
+			args: args{
+				total:     10,
+				different: 0,
+			},
+			want: &Percent{
+				value: 0.0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "everything different",
+			args: args{
+				total:     10,
+				different: 10,
+			},
+			want: &Percent{
+				value: 100.0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "more different than total",
+			args: args{
+				total:     10,
+				different: 25,
+			},
+			want: &Percent{
+				value: 100.0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "partially different",
+			args: args{
+				total:     20,
+				different: 5,
+			},
+			want: &Percent{
+				value: 25.0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty total without differences",
+			args: args{
+				total:     0,
+				different: 0,
+			},
+			want: &Percent{
+				value: 0.0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty total with differences",
+			args: args{
+				total:     0,
+				different: 3,
+			},
+			want: &Percent{
+				value: 100.0,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newDeviationPercent(tt.args.total, tt.args.different)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newDeviationPercent() error got = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newDeviationPercent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrMatchTypeRegistered is returned when a match type is registered twice or collides with a built-in match type.
+	ErrMatchTypeRegistered = errors.New("match type already registered")
+	// ErrInvalidMatchDefinition is returned when a match definition is incomplete.
+	ErrInvalidMatchDefinition = errors.New("invalid match definition")
+	// ErrInvalidMatchValue is returned when the match value of a validation cannot be parsed.
+	ErrInvalidMatchValue = errors.New("invalid match value")
+)
+
+// MatchDefinition defines a custom match type.
+type MatchDefinition struct {
+	// Description describes the match type in a human readable form.
+	Description string
+	// ParseMatchValue parses the match value of the validation.
+	// The result is passed to Evaluate. If nil, the match value is passed as *string.
+	ParseMatchValue func(matchValue string) (interface{}, error)
+	// Evaluate validates the value against the expected value and the parsed match value.
+	// If the validation is successful the function returns true as validation and nil as error.
+	Evaluate func(expected, matchValue, value interface{}) (bool, error)
+}
+
+// Registry holds custom match types.
+// A registry is safe for concurrent use. Every registry is isolated from all other registries.
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[MatchType]MatchDefinition
+}
+
+// DefaultRegistry is the registry used by validations that do not define their own registry.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a new and empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: map[MatchType]MatchDefinition{},
+	}
+}
+
+// RegisterMatchType registers the match type in the DefaultRegistry.
+func RegisterMatchType(matchType MatchType, definition MatchDefinition) error {
+	return DefaultRegistry.Register(matchType, definition)
+}
+
+// Register registers the match type with its definition.
+// An error is returned if the match type is built-in or already registered.
+func (r *Registry) Register(matchType MatchType, definition MatchDefinition) error {
+	if matchType == "" || definition.Evaluate == nil {
+		return fmt.Errorf("%w: match type %q requires a name and an evaluate function", ErrInvalidMatchDefinition, matchType)
+	}
+	if isBuiltinMatchType(matchType) {
+		return fmt.Errorf("%w: %s is a built-in match type", ErrMatchTypeRegistered, matchType)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.definitions[matchType]; ok {
+		return fmt.Errorf("%w: %s", ErrMatchTypeRegistered, matchType)
+	}
+	r.definitions[matchType] = definition
+	return nil
+}
+
+// Unregister removes the match type from the registry.
+func (r *Registry) Unregister(matchType MatchType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.definitions, matchType)
+}
+
+// Lookup returns the definition of the match type.
+func (r *Registry) Lookup(matchType MatchType) (MatchDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	definition, ok := r.definitions[matchType]
+	return definition, ok
+}
+
+// MatchTypes returns all registered match types in lexical order.
+func (r *Registry) MatchTypes() []MatchType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]MatchType, 0, len(r.definitions))
+	for matchType := range r.definitions {
+		types = append(types, matchType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// parseMatchValue parses the match value with the parser of the definition.
+func (definition MatchDefinition) parseMatchValue(matchValue *string) (interface{}, error) {
+	if definition.ParseMatchValue == nil {
+		return matchValue, nil
+	}
+	if matchValue == nil {
+		return nil, fmt.Errorf("%w: match value is missing", ErrInvalidMatchValue)
+	}
+	parsed, err := definition.ParseMatchValue(*matchValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMatchValue, err)
+	}
+	return parsed, nil
+}
+
+// isBuiltinMatchType reports whether the match type is handled by Validation.Matches itself.
+func isBuiltinMatchType(matchType MatchType) bool {
+	switch matchType {
+	case MatchTypeLessThan, MatchTypeLessThanOrEqual, MatchTypeGreaterThan, MatchTypeGreaterThanOrEqual,
+		MatchTypePercentageDeviation, MatchTypeRegex, MatchTypeRange, MatchTypeEqual, MatchTypeNotEqual,
+		MatchTypeEmpty, MatchTypeNotEmpty, MatchTypeContains, MatchTypeStartsWith, MatchTypeStartsWithIgnoreCase,
+		MatchTypeEndsWith, MatchTypeEndsWithIgnoreCase, MatchTypeGlob, MatchTypeGlobIgnoreCase, MatchTypeShape:
+		return true
+	}
+	return false
+}
+
+// Matches validates the value against the validation and resolves custom match types in the registry.
+// Nested validations of "matches shape" use the same registry.
+func (r *Registry) Matches(v Validation, value interface{}) (bool, error) {
+	return v.MatchesWithOptions(value, MatchOptions{Registry: r})
+}
+
+// Validate checks the validation like Validation.Validate and resolves custom match types in the registry.
+func (r *Registry) Validate(v Validation) error {
+	return v.validate(r)
+}
+
+// registry returns the registry of the options or the DefaultRegistry.
+func (o MatchOptions) registry() *Registry {
+	if o.Registry != nil {
+		return o.Registry
+	}
+	return DefaultRegistry
+}
+
+// matchRegistered evaluates a match type of the registry.
+func (d Validation) matchRegistered(value interface{}, registry *Registry) (bool, error) {
+	definition, ok := registry.Lookup(d.MatchType)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrInvalidMatchType, d.MatchType)
+	}
+	matchValue, err := definition.parseMatchValue(d.MatchValue)
+	if err != nil {
+		return false, err
+	}
+	return definition.Evaluate(d.ExpectedValue, matchValue, value)
+}
+
+// Validate checks that the match type is known and that the match value can be parsed.
+// Custom match types are resolved in the DefaultRegistry.
+func (d Validation) Validate() error {
+	return d.validate(DefaultRegistry)
+}
+
+// validate checks the validation and resolves custom match types in the registry.
+func (d Validation) validate(registry *Registry) error {
+	if !isBuiltinMatchType(d.MatchType) {
+		definition, ok := registry.Lookup(d.MatchType)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidMatchType, d.MatchType)
+		}
+		_, err := definition.parseMatchValue(d.MatchValue)
+		return err
+	}
+
+	var err error
+	switch d.MatchType {
+	case MatchTypePercentageDeviation, MatchTypeRegex, MatchTypeRange, MatchTypeContains,
+		MatchTypeStartsWith, MatchTypeStartsWithIgnoreCase, MatchTypeEndsWith, MatchTypeEndsWithIgnoreCase,
+		MatchTypeGlob, MatchTypeGlobIgnoreCase:
+		if d.MatchValue == nil {
+			return fmt.Errorf("%w: match type %s requires a match value", ErrInvalidMatchValue, d.MatchType)
+		}
+	default:
+		return nil
+	}
+	switch d.MatchType {
+	case MatchTypePercentageDeviation:
+		_, err = ParsePercentageValueFromString(*d.MatchValue)
+	case MatchTypeRegex:
+		_, err = regexp.Compile(*d.MatchValue)
+	case MatchTypeRange:
+		if !rangeRegex.MatchString(*d.MatchValue) {
+			err = fmt.Errorf("expected format [0-9]-[0-9], got %q", *d.MatchValue)
+		}
+	case MatchTypeGlob, MatchTypeGlobIgnoreCase:
+		err = validateGlob([]rune(*d.MatchValue))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMatchValue, err)
+	}
+	return nil
+}
+
+// rangeRegex matches a valid range definition.
+var rangeRegex = regexp.MustCompile(`^[0-9]+-[0-9]+$`)
@@ -0,0 +1,335 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// checksumDefinition validates that a value is a hex encoded checksum of the given length.
+var checksumDefinition = MatchDefinition{
+	Description: "hex encoded checksum of the given length",
+	ParseMatchValue: func(matchValue string) (interface{}, error) {
+		return strconv.Atoi(matchValue)
+	},
+	Evaluate: func(expected, matchValue, value interface{}) (bool, error) {
+		str, err := valueToString(value)
+		if err != nil {
+			return false, err
+		}
+		if len(str) != matchValue.(int) {
+			return false, nil
+		}
+		return strings.Trim(strings.ToLower(str), "0123456789abcdef") == "", nil
+	},
+}
+
+func TestRegistry_Register(t *testing.T) {
+	type args struct {
+		matchType  MatchType
+		definition MatchDefinition
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "valid definition",
+			args: args{
+				matchType:  "checksum",
+				definition: checksumDefinition,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "built-in match type",
+			args: args{
+				matchType:  MatchTypeEqual,
+				definition: checksumDefinition,
+			},
+			wantErr: ErrMatchTypeRegistered,
+		},
+		{
+			name: "missing evaluate function",
+			args: args{
+				matchType:  "checksum",
+				definition: MatchDefinition{Description: "missing"},
+			},
+			wantErr: ErrInvalidMatchDefinition,
+		},
+		{
+			name: "missing name",
+			args: args{
+				matchType:  "",
+				definition: checksumDefinition,
+			},
+			wantErr: ErrInvalidMatchDefinition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRegistry().Register(tt.args.matchType, tt.args.definition)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Registry.Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_RegisterTwice(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register("checksum", checksumDefinition); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	if err := r.Register("checksum", checksumDefinition); !errors.Is(err, ErrMatchTypeRegistered) {
+		t.Errorf("Registry.Register() error = %v, wantErr %v", err, ErrMatchTypeRegistered)
+	}
+	r.Unregister("checksum")
+	if err := r.Register("checksum", checksumDefinition); err != nil {
+		t.Errorf("Registry.Register() after Unregister() error = %v", err)
+	}
+}
+
+func TestRegistry_Isolation(t *testing.T) {
+	r1 := NewRegistry()
+	r2 := NewRegistry()
+	if err := r1.Register("checksum", checksumDefinition); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	if _, ok := r2.Lookup("checksum"); ok {
+		t.Errorf("Registry.Lookup() found match type of another registry")
+	}
+	if _, ok := DefaultRegistry.Lookup("checksum"); ok {
+		t.Errorf("DefaultRegistry.Lookup() found match type of another registry")
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	r := NewRegistry()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			matchType := MatchType(fmt.Sprintf("custom-%02d", i))
+			if err := r.Register(matchType, checksumDefinition); err != nil {
+				t.Errorf("Registry.Register() error = %v", err)
+			}
+			r.Lookup(matchType)
+			r.MatchTypes()
+		}(i)
+	}
+	wg.Wait()
+	if got := len(r.MatchTypes()); got != 50 {
+		t.Errorf("Registry.MatchTypes() returned %d match types, want %d", got, 50)
+	}
+}
+
+func TestRegistry_MatchTypes(t *testing.T) {
+	r := NewRegistry()
+	for _, matchType := range []MatchType{"zeta", "alpha", "mid"} {
+		if err := r.Register(matchType, checksumDefinition); err != nil {
+			t.Fatalf("Registry.Register() error = %v", err)
+		}
+	}
+	want := []MatchType{"alpha", "mid", "zeta"}
+	if got := r.MatchTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Registry.MatchTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestValidation_MatchesRegistered(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register("checksum", checksumDefinition); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	type fields struct {
+		MatchType  MatchType
+		MatchValue *string
+		Registry   *Registry
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		value   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "valid checksum",
+			fields: fields{
+				MatchType:  "checksum",
+				MatchValue: func() *string { s := "8"; return &s }(),
+				Registry:   r,
+			},
+			value:   "deadBEEF",
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "checksum with wrong length",
+			fields: fields{
+				MatchType:  "checksum",
+				MatchValue: func() *string { s := "8"; return &s }(),
+				Registry:   r,
+			},
+			value:   "dead",
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "invalid match value",
+			fields: fields{
+				MatchType:  "checksum",
+				MatchValue: func() *string { s := "eight"; return &s }(),
+				Registry:   r,
+			},
+			value:   "deadbeef",
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "missing match value",
+			fields: fields{
+				MatchType: "checksum",
+				Registry:  r,
+			},
+			value:   "deadbeef",
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "match type of another registry",
+			fields: fields{
+				MatchType:  "checksum",
+				MatchValue: func() *string { s := "8"; return &s }(),
+				Registry:   NewRegistry(),
+			},
+			value:   "deadbeef",
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Validation{
+				MatchType:  tt.fields.MatchType,
+				MatchValue: tt.fields.MatchValue,
+			}
+			got, err := d.MatchesWithOptions(tt.value, MatchOptions{Registry: tt.fields.Registry})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validation.Matches() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Validation.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidation_Validate(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register("checksum", checksumDefinition); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		validation Validation
+		registry   *Registry
+		wantErr    error
+	}{
+		{
+			name:       "built-in without match value",
+			validation: Validation{MatchType: MatchTypeEqual},
+			wantErr:    nil,
+		},
+		{
+			name:       "valid range",
+			validation: Validation{MatchType: MatchTypeRange, MatchValue: str("10-20")},
+			wantErr:    nil,
+		},
+		{
+			name:       "invalid range",
+			validation: Validation{MatchType: MatchTypeRange, MatchValue: str("ten-20")},
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "invalid percentage",
+			validation: Validation{MatchType: MatchTypePercentageDeviation, MatchValue: str("10")},
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "invalid regex",
+			validation: Validation{MatchType: MatchTypeRegex, MatchValue: str("[a-")},
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "invalid glob",
+			validation: Validation{MatchType: MatchTypeGlob, MatchValue: str("[a-")},
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "missing match value",
+			validation: Validation{MatchType: MatchTypeContains},
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "registered match type",
+			validation: Validation{MatchType: "checksum", MatchValue: str("40")},
+			registry:   r,
+			wantErr:    nil,
+		},
+		{
+			name:       "registered match type with invalid match value",
+			validation: Validation{MatchType: "checksum", MatchValue: str("forty")},
+			registry:   r,
+			wantErr:    ErrInvalidMatchValue,
+		},
+		{
+			name:       "unknown match type",
+			validation: Validation{MatchType: "checksum"},
+			wantErr:    ErrInvalidMatchType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := tt.validation.Validate
+			if tt.registry != nil {
+				validate = func() error { return tt.registry.Validate(tt.validation) }
+			}
+			if err := validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validation.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Matches_shape(t *testing.T) {
+	r := NewRegistry()
+	err := r.Register("always", MatchDefinition{
+		Evaluate: func(expected, matchValue, value interface{}) (bool, error) { return true, nil },
+	})
+	if err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	shape := Validation{
+		MatchType: MatchTypeShape,
+		ExpectedValue: map[string]interface{}{
+			"id": Validation{MatchType: "always"},
+		},
+	}
+	value := map[string]interface{}{"id": 7}
+	if got, err := r.Matches(shape, value); err != nil || !got {
+		t.Errorf("Registry.Matches() = %v, %v, want true, nil", got, err)
+	}
+	if _, err := shape.Matches(value); !errors.Is(err, ErrInvalidMatchType) {
+		t.Errorf("Validation.Matches() error = %v, want %v", err, ErrInvalidMatchType)
+	}
+}
@@ -0,0 +1,334 @@
+package compare
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ColorMode defines whether rendered diffs use ANSI colors.
+type ColorMode int
+
+const (
+	// ColorAuto uses colors if the output is a terminal, TERM is not "dumb" and NO_COLOR is not set.
+	ColorAuto ColorMode = iota
+	// ColorAlways always uses colors.
+	ColorAlways
+	// ColorNever never uses colors.
+	ColorNever
+)
+
+// DefaultRenderWidth defines the width used if neither RenderOptions.Width nor COLUMNS is set.
+const DefaultRenderWidth = 80
+
+// RenderOptions configures the terminal renderer.
+type RenderOptions struct {
+	// Color defines whether ANSI colors are used.
+	Color ColorMode
+	// Width defines the number of columns lines are wrapped at.
+	// If 0, the COLUMNS environment variable or DefaultRenderWidth is used.
+	// A negative width disables wrapping.
+	Width int
+	// SideBySide renders the old and new text in two columns instead of one below the other.
+	SideBySide bool
+}
+
+// ANSI escape sequences used by the renderer.
+const (
+	ansiReset             = "\x1b[0m"
+	ansiDeleted           = "\x1b[31m"
+	ansiInserted          = "\x1b[32m"
+	ansiDeletedHighlight  = "\x1b[1;37;41m"
+	ansiInsertedHighlight = "\x1b[1;37;42m"
+)
+
+// renderStyle defines how a run of text is rendered.
+type renderStyle int
+
+const (
+	styleEqual renderStyle = iota
+	styleDeleted
+	styleInserted
+	styleDeletedHighlight
+	styleInsertedHighlight
+)
+
+// styledText defines a run of text with a single style.
+type styledText struct {
+	style renderStyle
+	text  string
+}
+
+// RenderDiff writes the line diff of a and b to w. Changed lines are highlighted character
+// by character, changes that only consist of whitespace are made visible with "·" for spaces,
+// "→" for tabs and "␍" and "␊" for changed line terminators. Without colors, highlighted changes are marked as [-deleted-] and {+inserted+}.
+func RenderDiff(w io.Writer, a, b string, opts RenderOptions) error {
+	r := renderer{color: opts.useColor(w), width: opts.width()}
+	lines := DiffInline(a, b)
+	if opts.SideBySide {
+		r.sideBySide(lines)
+	} else {
+		r.inline(lines)
+	}
+	_, err := io.WriteString(w, r.out.String())
+	return err
+}
+
+// RenderReports applies the reports to original and writes the diff to w, see RenderDiff.
+func RenderReports(w io.Writer, original []byte, reports Reports, opts RenderOptions) error {
+	new, err := Apply(original, reports)
+	if err != nil {
+		return err
+	}
+	return RenderDiff(w, string(original), string(new), opts)
+}
+
+// useColor reports whether colors are used for w.
+func (o RenderOptions) useColor(w io.Writer) bool {
+	switch o.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// width returns the number of columns to wrap at, 0 disables wrapping.
+func (o RenderOptions) width() int {
+	if o.Width < 0 {
+		return 0
+	}
+	if o.Width > 0 {
+		return o.Width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultRenderWidth
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// renderer collects the rendered output.
+type renderer struct {
+	color bool
+	width int
+	out   strings.Builder
+}
+
+// inline renders the old version of a line above the new version.
+func (r *renderer) inline(lines []InlineLine) {
+	for _, line := range lines {
+		switch line.Kind {
+		case ReportKindEqual:
+			r.writeLine("  ", styleEqual, r.styled(line, line.Old, styleEqual))
+		default:
+			if line.Old != nil {
+				r.writeLine("- ", styleDeleted, r.styled(line, line.Old, styleDeleted))
+			}
+			if line.New != nil {
+				r.writeLine("+ ", styleInserted, r.styled(line, line.New, styleInserted))
+			}
+		}
+	}
+}
+
+// sideBySide renders the old and new version of a line next to each other,
+// separated by a marker like diff --side-by-side: "|" changed, "<" deleted and ">" inserted.
+func (r *renderer) sideBySide(lines []InlineLine) {
+	column := 0
+	if r.width > 0 {
+		column = (r.width - 3) / 2
+		if column < 1 {
+			column = 1
+		}
+	}
+	for _, line := range lines {
+		marker := " | "
+		switch line.Kind {
+		case ReportKindEqual:
+			marker = "   "
+		case ReportKindDeleted:
+			marker = " < "
+		case ReportKindInserted:
+			marker = " > "
+		}
+		oldStyle, newStyle := styleDeleted, styleInserted
+		if line.Kind == ReportKindEqual {
+			oldStyle, newStyle = styleEqual, styleEqual
+		}
+		left := wrapStyled(r.styled(line, line.Old, oldStyle), column)
+		right := wrapStyled(r.styled(line, line.New, newStyle), column)
+		rows := len(left)
+		if len(right) > rows {
+			rows = len(right)
+		}
+		leftWidth := column
+		if leftWidth == 0 {
+			// without wrapping the left column is as wide as its longest row
+			for _, row := range left {
+				if n := styledWidth(row); n > leftWidth {
+					leftWidth = n
+				}
+			}
+		}
+		for i := 0; i < rows; i++ {
+			var row []styledText
+			if i < len(left) {
+				row = left[i]
+			}
+			r.write(row)
+			r.out.WriteString(strings.Repeat(" ", leftWidth-styledWidth(row)))
+			r.out.WriteString(marker)
+			if i < len(right) {
+				r.write(right[i])
+			}
+			r.out.WriteString("\n")
+			marker = strings.Repeat(" ", len(marker))
+		}
+	}
+}
+
+// styled converts the segments of a line into styled runs. Equal segments use the style
+// of the line, deleted and inserted segments of changed lines are highlighted.
+func (r *renderer) styled(line InlineLine, segments Segments, style renderStyle) []styledText {
+	runs := []styledText{}
+	for _, segment := range segments {
+		text := expandTabs(segment.Text)
+		runStyle := style
+		if line.Kind == ReportKindChanged && segment.Kind != ReportKindEqual {
+			runStyle = styleInsertedHighlight
+			if segment.Kind == ReportKindDeleted {
+				runStyle = styleDeletedHighlight
+			}
+			if isWhitespace(segment.Text) {
+				text = visibleWhitespace(segment.Text)
+			}
+			if !r.color {
+				if runStyle == styleDeletedHighlight {
+					text = "[-" + text + "-]"
+				} else {
+					text = "{+" + text + "+}"
+				}
+			}
+		}
+		runs = append(runs, styledText{style: runStyle, text: text})
+	}
+	return runs
+}
+
+// writeLine writes the runs wrapped at the width of the renderer with the prefix
+// in front of every row.
+func (r *renderer) writeLine(prefix string, style renderStyle, runs []styledText) {
+	column := 0
+	if r.width > 0 {
+		column = r.width - utf8.RuneCountInString(prefix)
+		if column < 1 {
+			column = 1
+		}
+	}
+	for _, row := range wrapStyled(runs, column) {
+		r.write([]styledText{{style: style, text: prefix}})
+		r.write(row)
+		r.out.WriteString("\n")
+	}
+}
+
+// write writes the runs with ANSI colors if colors are enabled.
+func (r *renderer) write(runs []styledText) {
+	for _, run := range runs {
+		code := ""
+		if r.color {
+			switch run.style {
+			case styleDeleted:
+				code = ansiDeleted
+			case styleInserted:
+				code = ansiInserted
+			case styleDeletedHighlight:
+				code = ansiDeletedHighlight
+			case styleInsertedHighlight:
+				code = ansiInsertedHighlight
+			}
+		}
+		if code == "" {
+			r.out.WriteString(run.text)
+			continue
+		}
+		r.out.WriteString(code + run.text + ansiReset)
+	}
+}
+
+// wrapStyled splits the runs into rows of at most width runes. A width of 0 disables wrapping.
+// An empty line results in a single empty row.
+func wrapStyled(runs []styledText, width int) [][]styledText {
+	rows := [][]styledText{{}}
+	used := 0
+	for _, run := range runs {
+		text := run.text
+		for text != "" {
+			if width > 0 && used == width {
+				rows = append(rows, []styledText{})
+				used = 0
+			}
+			n := len(text)
+			if width > 0 {
+				n = runeOffset(text, width-used)
+			}
+			last := len(rows) - 1
+			rows[last] = append(rows[last], styledText{style: run.style, text: text[:n]})
+			used += utf8.RuneCountInString(text[:n])
+			text = text[n:]
+		}
+	}
+	return rows
+}
+
+// styledWidth returns the number of runes of the runs.
+func styledWidth(runs []styledText) int {
+	n := 0
+	for _, run := range runs {
+		n += utf8.RuneCountInString(run.text)
+	}
+	return n
+}
+
+// runeOffset returns the byte offset of the rune at index n of s, or len(s) if s is shorter.
+func runeOffset(s string, n int) int {
+	for offset := range s {
+		if n == 0 {
+			return offset
+		}
+		n--
+	}
+	return len(s)
+}
+
+// expandTabs replaces tabs with four spaces, so that the width of a line is its number of runes.
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// isWhitespace reports whether s is not empty and only consists of whitespace.
+func isWhitespace(s string) bool {
+	return s != "" && strings.TrimFunc(s, unicode.IsSpace) == ""
+}
+
+// visibleWhitespace replaces spaces with "·", tabs with "→" and line terminators with "␍" and "␊".
+func visibleWhitespace(s string) string {
+	return strings.NewReplacer(" ", "·", "\t", "→", "\r", "␍", "\n", "␊").Replace(s)
+}
@@ -0,0 +1,172 @@
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRenderDiff(t *testing.T) {
+	type args struct {
+		a    string
+		b    string
+		opts RenderOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "inline without colors",
+			args: args{
+				a:    "keep\nerror: file not found\nremoved\n",
+				b:    "keep\nerror: file not saved\n",
+				opts: RenderOptions{Color: ColorNever, Width: -1},
+			},
+			want: "  keep\n" +
+				"- error: file not [-foun-]d\n" +
+				"+ error: file not {+save+}d\n" +
+				"- removed\n",
+		},
+		{
+			name: "inline with colors",
+			args: args{
+				a:    "a b\n",
+				b:    "a c\n",
+				opts: RenderOptions{Color: ColorAlways, Width: -1},
+			},
+			want: "\x1b[31m- \x1b[0m\x1b[31ma \x1b[0m\x1b[1;37;41mb\x1b[0m\n" +
+				"\x1b[32m+ \x1b[0m\x1b[32ma \x1b[0m\x1b[1;37;42mc\x1b[0m\n",
+		},
+		{
+			name: "whitespace only change is visible",
+			args: args{
+				a:    "x = 1 \n",
+				b:    "x = 1\t\n",
+				opts: RenderOptions{Color: ColorNever, Width: -1},
+			},
+			want: "- x = 1[-·-]\n" +
+				"+ x = 1{+→+}\n",
+		},
+		{
+			name: "line terminator change is visible",
+			args: args{
+				a:    "a\r\nb\r\n",
+				b:    "a\nb\n",
+				opts: RenderOptions{Color: ColorNever, Width: -1},
+			},
+			want: "- a[-␍␊-]\n" +
+				"+ a{+␊+}\n" +
+				"- b[-␍␊-]\n" +
+				"+ b{+␊+}\n",
+		},
+		{
+			name: "missing final line terminator is visible",
+			args: args{
+				a:    "a\n",
+				b:    "a",
+				opts: RenderOptions{Color: ColorNever, Width: -1},
+			},
+			want: "- a[-␊-]\n" +
+				"+ a\n",
+		},
+		{
+			name: "inline wraps at width",
+			args: args{
+				a:    "",
+				b:    "0123456789",
+				opts: RenderOptions{Color: ColorNever, Width: 6},
+			},
+			want: "+ 0123\n" +
+				"+ 4567\n" +
+				"+ 89\n",
+		},
+		{
+			name: "side by side",
+			args: args{
+				a:    "same\nold\ngone\n",
+				b:    "same\nnew\n",
+				opts: RenderOptions{Color: ColorNever, Width: 23, SideBySide: true},
+			},
+			want: "same         same\n" +
+				"[-old-]    | {+new+}\n" +
+				"gone       < \n",
+		},
+		{
+			name: "side by side wraps both columns",
+			args: args{
+				a:    "abcdefgh\n",
+				b:    "abcd\n",
+				opts: RenderOptions{Color: ColorNever, Width: 11, SideBySide: true},
+			},
+			want: "abcd | abcd\n" +
+				"[-ef   \n" +
+				"gh-]   \n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			if err := RenderDiff(&buf, tt.args.a, tt.args.b, tt.args.opts); err != nil {
+				t.Fatalf("RenderDiff() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("RenderDiff() = \n%q\n, want \n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderReports(t *testing.T) {
+	original := []byte("hello\n")
+	buf := bytes.Buffer{}
+	if err := RenderReports(&buf, original, BytesDiff(original, []byte("help\n")), RenderOptions{Color: ColorNever}); err != nil {
+		t.Fatalf("RenderReports() error = %v", err)
+	}
+	want := "- hel[-lo-]\n" +
+		"+ hel{+p+}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("RenderReports() = %q, want %q", got, want)
+	}
+
+	x := byte('x')
+	err := RenderReports(&buf, original, Reports{{Kind: ReportKindDeleted, Index: 0, Original: &x}}, RenderOptions{})
+	if !errors.Is(err, ErrInconsistentReports) {
+		t.Errorf("RenderReports() error = %v, wantErr %v", err, ErrInconsistentReports)
+	}
+}
+
+func TestRenderOptions_useColor(t *testing.T) {
+	if (RenderOptions{Color: ColorAuto}).useColor(&bytes.Buffer{}) {
+		t.Errorf("useColor() = true for a buffer, want false")
+	}
+	if !(RenderOptions{Color: ColorAlways}).useColor(&bytes.Buffer{}) {
+		t.Errorf("useColor() = false for ColorAlways, want true")
+	}
+	if (RenderOptions{Color: ColorNever}).useColor(os.Stdout) {
+		t.Errorf("useColor() = true for ColorNever, want false")
+	}
+	t.Setenv("NO_COLOR", "")
+	if (RenderOptions{Color: ColorAuto}).useColor(os.Stdout) {
+		t.Errorf("useColor() = true with NO_COLOR set, want false")
+	}
+}
+
+func TestRenderOptions_width(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if got := (RenderOptions{}).width(); got != DefaultRenderWidth {
+		t.Errorf("width() = %v, want %v", got, DefaultRenderWidth)
+	}
+	t.Setenv("COLUMNS", "120")
+	if got := (RenderOptions{}).width(); got != 120 {
+		t.Errorf("width() = %v, want %v", got, 120)
+	}
+	if got := (RenderOptions{Width: 40}).width(); got != 40 {
+		t.Errorf("width() = %v, want %v", got, 40)
+	}
+	if got := (RenderOptions{Width: -1}).width(); got != 0 {
+		t.Errorf("width() = %v, want %v", got, 0)
+	}
+}
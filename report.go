@@ -9,22 +9,70 @@ func (r *Reports) swap() {
 	}
 }
 
+// ReportKind defines the kind of difference described by a report.
+type ReportKind int
+
+const (
+	// ReportKindChanged is used if the value at index differs between A and B.
+	ReportKindChanged ReportKind = iota
+	// ReportKindInserted is used if the value at index only exists in B.
+	ReportKindInserted
+	// ReportKindDeleted is used if the value at index only exists in A.
+	ReportKindDeleted
+	// ReportKindEqual is used if the value exists unchanged in A and B.
+	ReportKindEqual
+)
+
+// String returns the name of the report kind.
+func (k ReportKind) String() string {
+	switch k {
+	case ReportKindChanged:
+		return "changed"
+	case ReportKindInserted:
+		return "inserted"
+	case ReportKindDeleted:
+		return "deleted"
+	case ReportKindEqual:
+		return "equal"
+	default:
+		return "unknown"
+	}
+}
+
 type Report struct {
 	// Type defines the actual type of A and B
 	// This field has no functional purpose and only exists for discovery.
 	Type string
+	// Kind defines whether the byte was changed, inserted or deleted.
+	Kind ReportKind
 	// Index is the index of the byte that differs
+	// For inserted bytes the index refers to B, otherwise it refers to A.
 	Index int
 	// Original represents the value of the byte at index in A
+	// Original is nil if the byte was inserted.
 	Original *byte
 	// New defines the value of b passed to the Equal function.
+	// New is zero if the byte was deleted.
 	New byte
 }
 
 // swap swaps the values of a and b.
+// Inserted bytes become deleted bytes and vice versa.
 func (r *Report) swap() {
-	new := r.New
-	orig := r.Original
-	r.Original = &new
-	r.New = *orig
+	switch r.Kind {
+	case ReportKindInserted:
+		new := r.New
+		r.Kind = ReportKindDeleted
+		r.Original = &new
+		r.New = 0
+	case ReportKindDeleted:
+		r.Kind = ReportKindInserted
+		r.New = *r.Original
+		r.Original = nil
+	default:
+		new := r.New
+		orig := r.Original
+		r.Original = &new
+		r.New = *orig
+	}
 }
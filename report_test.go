@@ -167,6 +167,40 @@ func TestReport_swap(t *testing.T) {
 				New:      1,
 			},
 		},
+		{
+			name: "swap inserted",
+			inReport: Report{
+				Type:     "uint8",
+				Kind:     ReportKindInserted,
+				Index:    3,
+				Original: nil,
+				New:      'x',
+			},
+			want: Report{
+				Type:     "uint8",
+				Kind:     ReportKindDeleted,
+				Index:    3,
+				Original: func() *byte { b := byte('x'); return &b }(),
+				New:      0,
+			},
+		},
+		{
+			name: "swap deleted",
+			inReport: Report{
+				Type:     "uint8",
+				Kind:     ReportKindDeleted,
+				Index:    3,
+				Original: func() *byte { b := byte('x'); return &b }(),
+				New:      0,
+			},
+			want: Report{
+				Type:     "uint8",
+				Kind:     ReportKindInserted,
+				Index:    3,
+				Original: nil,
+				New:      'x',
+			},
+		},
 		{
 			name: "swap chars and number",
 			inReport: Report{
@@ -193,3 +227,44 @@ func TestReport_swap(t *testing.T) {
 		})
 	}
 }
+
+func TestReportKind_String(t *testing.T) {
+	tests := []struct {
+		name string
+		k    ReportKind
+		want string
+	}{
+		{
+			name: "changed",
+			k:    ReportKindChanged,
+			want: "changed",
+		},
+		{
+			name: "inserted",
+			k:    ReportKindInserted,
+			want: "inserted",
+		},
+		{
+			name: "deleted",
+			k:    ReportKindDeleted,
+			want: "deleted",
+		},
+		{
+			name: "equal",
+			k:    ReportKindEqual,
+			want: "equal",
+		},
+		{
+			name: "unknown",
+			k:    ReportKind(42),
+			want: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.k.String(); got != tt.want {
+				t.Errorf("ReportKind.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,751 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var (
+	// ErrInvalidSchema is returned when encoded diff results do not follow the schema.
+	ErrInvalidSchema = errors.New("invalid diff result encoding")
+	// ErrUnsupportedSchemaVersion is returned when encoded diff results use an unknown schema version.
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+)
+
+// SchemaVersion defines the version of the JSON encoding of diff results.
+// The version is increased whenever the encoding changes in an incompatible way.
+//
+// Diff results are encoded as a document with the schema version, the kind of the
+// results and the results themselves:
+//
+//	{"version": 1, "kind": "bytes", "reports": [...]}
+//
+// The kind names the encoded type:
+//   - "bytes": Reports
+//   - "values": ValueReports
+//   - "sequence": SequenceReports
+//   - "text": TextReports
+//   - "segments": Segments
+//   - "hunks": Hunks
+//   - "unified": UnifiedDiff, reports holds a single object instead of an array
+//   - "bits": BitReports
+//
+// Version 0 is the unversioned encoding of Reports in earlier releases: a plain JSON array
+// with the field names of the Report struct, e.g. [{"Type":"uint8","Index":3,"Original":97,"New":98}].
+// These reports have no kind: a null original marks an inserted byte, any other report is a
+// changed byte. Reports.UnmarshalJSON still decodes version 0, MarshalJSON always writes the
+// current version.
+const SchemaVersion = 1
+
+// Kinds of diff results in the JSON encoding.
+const (
+	schemaKindBytes    = "bytes"
+	schemaKindValues   = "values"
+	schemaKindSequence = "sequence"
+	schemaKindText     = "text"
+	schemaKindSegments = "segments"
+	schemaKindHunks    = "hunks"
+	schemaKindUnified  = "unified"
+	schemaKindBits     = "bits"
+)
+
+// schemaDocument defines the envelope of encoded diff results.
+type schemaDocument struct {
+	Version int             `json:"version"`
+	Kind    string          `json:"kind"`
+	Reports json.RawMessage `json:"reports"`
+}
+
+// legacyReportJSON defines the version 0 encoding of a Report.
+// Kind is only set by releases that distinguish deleted bytes, otherwise it is inferred.
+type legacyReportJSON struct {
+	Type     string
+	Kind     *int
+	Index    int
+	Original *byte
+	New      byte
+}
+
+// reportJSON defines the encoding of a Report:
+//
+//	{"type": "uint8", "kind": "changed", "index": 3, "original": 97, "new": 98}
+//
+// kind is one of "changed", "inserted" and "deleted". original and new are the byte values
+// as numbers from 0 to 255. original is null for inserted bytes, new is null for deleted bytes.
+// index refers to the new data for inserted bytes and to the original data otherwise.
+type reportJSON struct {
+	Type     string     `json:"type"`
+	Kind     ReportKind `json:"kind"`
+	Index    int        `json:"index"`
+	Original *byte      `json:"original"`
+	New      *byte      `json:"new"`
+}
+
+// sequenceReportJSON defines the encoding of a SequenceReport:
+//
+//	{"type": "string", "kind": "changed", "index": 1, "original": "b", "new": "x"}
+//
+// original is omitted for inserted elements, new is omitted for deleted elements.
+// The elements are encoded with encoding/json.
+type sequenceReportJSON struct {
+	Type     string          `json:"type"`
+	Kind     ReportKind      `json:"kind"`
+	Index    int             `json:"index"`
+	Original json.RawMessage `json:"original,omitempty"`
+	New      json.RawMessage `json:"new,omitempty"`
+}
+
+// hunkJSON defines the encoding of a Hunk:
+//
+//	{"oldStart": 0, "oldEnd": 3, "newStart": 0, "newEnd": 3, "old": [97, 98, 99], "new": [97, 120, 99],
+//	 "changed": 1, "inserted": 0, "deleted": 0}
+//
+// old and new are the bytes as numbers from 0 to 255, like the bytes of reportJSON.
+type hunkJSON struct {
+	OldStart int   `json:"oldStart"`
+	OldEnd   int   `json:"oldEnd"`
+	NewStart int   `json:"newStart"`
+	NewEnd   int   `json:"newEnd"`
+	Old      []int `json:"old"`
+	New      []int `json:"new"`
+	Changed  int   `json:"changed"`
+	Inserted int   `json:"inserted"`
+	Deleted  int   `json:"deleted"`
+}
+
+// bitReportJSON defines the encoding of a BitReport, the fields of reportJSON followed by the bits:
+//
+//	{"type": "uint8", "kind": "changed", "index": 3, "original": 97, "new": 99, "order": "msb", "mask": 2, "bits": [6]}
+type bitReportJSON struct {
+	reportJSON
+	Order BitOrder `json:"order"`
+	Mask  byte     `json:"mask"`
+	Bits  []int    `json:"bits"`
+}
+
+// valueReportJSON defines the encoding of a ValueReport:
+//
+//	{"path": "users[2].email", "kind": "changed", "old": "a@x", "new": "b@x"}
+//
+// old is omitted for inserted values, new is omitted for deleted values.
+// The values are encoded with encoding/json.
+type valueReportJSON struct {
+	Path string          `json:"path"`
+	Kind ReportKind      `json:"kind"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// MarshalText encodes the kind by its name.
+func (k ReportKind) MarshalText() ([]byte, error) {
+	if k.String() == "unknown" {
+		return nil, fmt.Errorf("%w: unknown report kind %d", ErrInvalidSchema, int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes the kind from its name.
+func (k *ReportKind) UnmarshalText(text []byte) error {
+	for _, kind := range []ReportKind{ReportKindChanged, ReportKindInserted, ReportKindDeleted, ReportKindEqual} {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown report kind %q", ErrInvalidSchema, text)
+}
+
+// MarshalText encodes the operation by its name.
+func (op EditOp) MarshalText() ([]byte, error) {
+	if op.String() == "unknown" {
+		return nil, fmt.Errorf("%w: unknown edit operation %d", ErrInvalidSchema, int(op))
+	}
+	return []byte(op.String()), nil
+}
+
+// UnmarshalText decodes the operation from its name.
+func (op *EditOp) UnmarshalText(text []byte) error {
+	for _, o := range []EditOp{EditEqual, EditInsert, EditDelete} {
+		if o.String() == string(text) {
+			*op = o
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown edit operation %q", ErrInvalidSchema, text)
+}
+
+// MarshalText encodes the bit order as "msb" or "lsb".
+func (o BitOrder) MarshalText() ([]byte, error) {
+	switch o {
+	case BitOrderMSB:
+		return []byte("msb"), nil
+	case BitOrderLSB:
+		return []byte("lsb"), nil
+	}
+	return nil, fmt.Errorf("%w: unknown bit order %d", ErrInvalidSchema, int(o))
+}
+
+// UnmarshalText decodes the bit order from "msb" or "lsb".
+func (o *BitOrder) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "msb":
+		*o = BitOrderMSB
+	case "lsb":
+		*o = BitOrderLSB
+	default:
+		return fmt.Errorf("%w: unknown bit order %q", ErrInvalidSchema, text)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the reports as versioned document of kind "bytes", see SchemaVersion.
+func (r Reports) MarshalJSON() ([]byte, error) {
+	reports := make([]reportJSON, len(r))
+	for i, report := range r {
+		encoded, err := encodeReport(report)
+		if err != nil {
+			return nil, fmt.Errorf("report %d: %w", i, err)
+		}
+		reports[i] = encoded
+	}
+	return marshalSchema(schemaKindBytes, reports)
+}
+
+// encodeReport converts the report into its encoding.
+func encodeReport(report Report) (reportJSON, error) {
+	encoded := reportJSON{Type: report.Type, Kind: report.Kind, Index: report.Index}
+	switch report.Kind {
+	case ReportKindChanged, ReportKindDeleted:
+		if report.Original == nil {
+			return reportJSON{}, fmt.Errorf("%w: original of %s byte is nil", ErrInvalidSchema, report.Kind)
+		}
+		encoded.Original = report.Original
+	case ReportKindInserted:
+	default:
+		return reportJSON{}, fmt.Errorf("%w: unsupported report kind %s", ErrInvalidSchema, report.Kind)
+	}
+	if report.Kind != ReportKindDeleted {
+		new := report.New
+		encoded.New = &new
+	}
+	return encoded, nil
+}
+
+// UnmarshalJSON decodes reports encoded by MarshalJSON or by the version 0 encoding.
+func (r *Reports) UnmarshalJSON(data []byte) error {
+	encoded := []reportJSON{}
+	if isJSONArray(data) {
+		legacy := []legacyReportJSON{}
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+		}
+		for _, report := range legacy {
+			e := reportJSON{Type: report.Type, Kind: ReportKindChanged, Index: report.Index, Original: report.Original}
+			switch {
+			case report.Kind != nil:
+				e.Kind = ReportKind(*report.Kind)
+			case report.Original == nil:
+				e.Kind = ReportKindInserted
+			}
+			if e.Kind != ReportKindDeleted {
+				new := report.New
+				e.New = &new
+			}
+			encoded = append(encoded, e)
+		}
+	} else if err := unmarshalSchema(data, schemaKindBytes, &encoded); err != nil {
+		return err
+	}
+	reports := make(Reports, len(encoded))
+	for i, report := range encoded {
+		decoded, err := report.decode()
+		if err != nil {
+			return fmt.Errorf("report %d: %w", i, err)
+		}
+		reports[i] = decoded
+	}
+	*r = reports
+	return nil
+}
+
+// decode converts the encoded report and checks that the values match the kind.
+func (e reportJSON) decode() (Report, error) {
+	report := Report{Type: e.Type, Kind: e.Kind, Index: e.Index, Original: e.Original}
+	if e.Index < 0 {
+		return Report{}, fmt.Errorf("%w: negative index %d", ErrInvalidSchema, e.Index)
+	}
+	if e.Kind != ReportKindChanged && e.Kind != ReportKindInserted && e.Kind != ReportKindDeleted {
+		return Report{}, fmt.Errorf("%w: unsupported report kind %s", ErrInvalidSchema, e.Kind)
+	}
+	if (e.Original == nil) != (e.Kind == ReportKindInserted) {
+		return Report{}, fmt.Errorf("%w: original must be null if and only if the byte was inserted", ErrInvalidSchema)
+	}
+	if (e.New == nil) != (e.Kind == ReportKindDeleted) {
+		return Report{}, fmt.Errorf("%w: new must be null if and only if the byte was deleted", ErrInvalidSchema)
+	}
+	if e.New != nil {
+		report.New = *e.New
+	}
+	return report, nil
+}
+
+// MarshalJSON encodes the reports as versioned document of kind "values", see SchemaVersion.
+func (r ValueReports) MarshalJSON() ([]byte, error) {
+	reports := make([]valueReportJSON, len(r))
+	for i, report := range r {
+		encoded := valueReportJSON{Path: report.Path, Kind: report.Kind}
+		if report.Kind != ReportKindInserted {
+			old, err := json.Marshal(report.Old)
+			if err != nil {
+				return nil, fmt.Errorf("report %d: %w", i, err)
+			}
+			encoded.Old = old
+		}
+		if report.Kind != ReportKindDeleted {
+			new, err := json.Marshal(report.New)
+			if err != nil {
+				return nil, fmt.Errorf("report %d: %w", i, err)
+			}
+			encoded.New = new
+		}
+		reports[i] = encoded
+	}
+	return marshalSchema(schemaKindValues, reports)
+}
+
+// UnmarshalJSON decodes reports encoded by MarshalJSON.
+// Values are decoded into the types of encoding/json: numbers are json.Number,
+// objects are map[string]interface{} and arrays []interface{}.
+func (r *ValueReports) UnmarshalJSON(data []byte) error {
+	encoded := []valueReportJSON{}
+	if err := unmarshalSchema(data, schemaKindValues, &encoded); err != nil {
+		return err
+	}
+	reports := make(ValueReports, len(encoded))
+	for i, report := range encoded {
+		decoded, err := report.decode()
+		if err != nil {
+			return fmt.Errorf("report %d: %w", i, err)
+		}
+		reports[i] = decoded
+	}
+	*r = reports
+	return nil
+}
+
+// decode converts the encoded report and checks that the values match the kind.
+func (e valueReportJSON) decode() (ValueReport, error) {
+	if (e.Old == nil) != (e.Kind == ReportKindInserted) || (e.New == nil) != (e.Kind == ReportKindDeleted) || e.Kind == ReportKindEqual {
+		return ValueReport{}, fmt.Errorf("%w: old and new do not match kind %s", ErrInvalidSchema, e.Kind)
+	}
+	report := ValueReport{Path: e.Path, Kind: e.Kind}
+	var err error
+	if e.Old != nil {
+		if report.Old, err = decodeJSON(e.Old); err != nil {
+			return ValueReport{}, fmt.Errorf("%w: old: %v", ErrInvalidSchema, err)
+		}
+	}
+	if e.New != nil {
+		if report.New, err = decodeJSON(e.New); err != nil {
+			return ValueReport{}, fmt.Errorf("%w: new: %v", ErrInvalidSchema, err)
+		}
+	}
+	return report, nil
+}
+
+// MarshalJSON encodes the reports as versioned document of kind "sequence", see SchemaVersion.
+func (r SequenceReports) MarshalJSON() ([]byte, error) {
+	reports := make([]sequenceReportJSON, len(r))
+	for i, report := range r {
+		encoded := sequenceReportJSON{Type: report.Type, Kind: report.Kind, Index: report.Index}
+		var err error
+		if report.Kind != ReportKindInserted {
+			if encoded.Original, err = json.Marshal(report.Original); err != nil {
+				return nil, fmt.Errorf("report %d: %w", i, err)
+			}
+		}
+		if report.Kind != ReportKindDeleted {
+			if encoded.New, err = json.Marshal(report.New); err != nil {
+				return nil, fmt.Errorf("report %d: %w", i, err)
+			}
+		}
+		reports[i] = encoded
+	}
+	return marshalSchema(schemaKindSequence, reports)
+}
+
+// UnmarshalJSON decodes reports encoded by MarshalJSON.
+// Elements are decoded into the types of encoding/json like the values of ValueReports.
+func (r *SequenceReports) UnmarshalJSON(data []byte) error {
+	encoded := []sequenceReportJSON{}
+	if err := unmarshalSchema(data, schemaKindSequence, &encoded); err != nil {
+		return err
+	}
+	reports := make(SequenceReports, len(encoded))
+	for i, report := range encoded {
+		if (report.Original == nil) != (report.Kind == ReportKindInserted) || (report.New == nil) != (report.Kind == ReportKindDeleted) || report.Kind == ReportKindEqual {
+			return fmt.Errorf("report %d: %w: original and new do not match kind %s", i, ErrInvalidSchema, report.Kind)
+		}
+		decoded := SequenceReport{Type: report.Type, Kind: report.Kind, Index: report.Index}
+		var err error
+		if report.Original != nil {
+			if decoded.Original, err = decodeJSON(report.Original); err != nil {
+				return fmt.Errorf("report %d: %w", i, err)
+			}
+		}
+		if report.New != nil {
+			if decoded.New, err = decodeJSON(report.New); err != nil {
+				return fmt.Errorf("report %d: %w", i, err)
+			}
+		}
+		reports[i] = decoded
+	}
+	*r = reports
+	return nil
+}
+
+// MarshalJSON encodes the reports as versioned document of kind "text", see SchemaVersion.
+// The reports use the json names of TextReport and TextPosition.
+func (r TextReports) MarshalJSON() ([]byte, error) {
+	return marshalSchema(schemaKindText, []TextReport(r))
+}
+
+// UnmarshalJSON decodes reports encoded by MarshalJSON.
+func (r *TextReports) UnmarshalJSON(data []byte) error {
+	reports := []TextReport{}
+	if err := unmarshalSchema(data, schemaKindText, &reports); err != nil {
+		return err
+	}
+	*r = reports
+	return nil
+}
+
+// MarshalJSON encodes the segments as versioned document of kind "segments", see SchemaVersion:
+//
+//	[{"kind": "equal", "text": "file not "}, {"kind": "deleted", "text": "found"}]
+func (s Segments) MarshalJSON() ([]byte, error) {
+	return marshalSchema(schemaKindSegments, []Segment(s))
+}
+
+// UnmarshalJSON decodes segments encoded by MarshalJSON.
+func (s *Segments) UnmarshalJSON(data []byte) error {
+	segments := []Segment{}
+	if err := unmarshalSchema(data, schemaKindSegments, &segments); err != nil {
+		return err
+	}
+	*s = segments
+	return nil
+}
+
+// MarshalJSON encodes the hunks as versioned document of kind "hunks", see SchemaVersion.
+func (h Hunks) MarshalJSON() ([]byte, error) {
+	hunks := make([]hunkJSON, len(h))
+	for i, hunk := range h {
+		hunks[i] = hunkJSON{
+			OldStart: hunk.OldStart,
+			OldEnd:   hunk.OldEnd,
+			NewStart: hunk.NewStart,
+			NewEnd:   hunk.NewEnd,
+			Old:      bytesToInts(hunk.Old),
+			New:      bytesToInts(hunk.New),
+			Changed:  hunk.Changed,
+			Inserted: hunk.Inserted,
+			Deleted:  hunk.Deleted,
+		}
+	}
+	return marshalSchema(schemaKindHunks, hunks)
+}
+
+// UnmarshalJSON decodes hunks encoded by MarshalJSON.
+func (h *Hunks) UnmarshalJSON(data []byte) error {
+	encoded := []hunkJSON{}
+	if err := unmarshalSchema(data, schemaKindHunks, &encoded); err != nil {
+		return err
+	}
+	hunks := make(Hunks, len(encoded))
+	for i, hunk := range encoded {
+		old, err := intsToBytes(hunk.Old)
+		if err != nil {
+			return fmt.Errorf("hunk %d: %w", i, err)
+		}
+		new, err := intsToBytes(hunk.New)
+		if err != nil {
+			return fmt.Errorf("hunk %d: %w", i, err)
+		}
+		if len(old) != hunk.OldEnd-hunk.OldStart || len(new) != hunk.NewEnd-hunk.NewStart {
+			return fmt.Errorf("hunk %d: %w: data does not match the offsets", i, ErrInvalidSchema)
+		}
+		hunks[i] = Hunk{
+			OldStart: hunk.OldStart,
+			OldEnd:   hunk.OldEnd,
+			NewStart: hunk.NewStart,
+			NewEnd:   hunk.NewEnd,
+			Old:      old,
+			New:      new,
+			Changed:  hunk.Changed,
+			Inserted: hunk.Inserted,
+			Deleted:  hunk.Deleted,
+		}
+	}
+	*h = hunks
+	return nil
+}
+
+// bytesToInts converts the bytes into numbers, so they are not encoded as base64.
+func bytesToInts(b []byte) []int {
+	ints := make([]int, len(b))
+	for i, v := range b {
+		ints[i] = int(v)
+	}
+	return ints
+}
+
+// intsToBytes converts numbers from 0 to 255 into bytes.
+func intsToBytes(ints []int) ([]byte, error) {
+	b := make([]byte, len(ints))
+	for i, v := range ints {
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("%w: byte %d", ErrInvalidSchema, v)
+		}
+		b[i] = byte(v)
+	}
+	return b, nil
+}
+
+// unifiedDiffJSON has the fields of UnifiedDiff without its JSON methods.
+type unifiedDiffJSON UnifiedDiff
+
+// MarshalJSON encodes the diff as versioned document of kind "unified", see SchemaVersion.
+// The diff uses the json names of UnifiedDiff, UnifiedHunk and UnifiedLine.
+func (d UnifiedDiff) MarshalJSON() ([]byte, error) {
+	return marshalSchema(schemaKindUnified, unifiedDiffJSON(d))
+}
+
+// UnmarshalJSON decodes a diff encoded by MarshalJSON.
+func (d *UnifiedDiff) UnmarshalJSON(data []byte) error {
+	decoded := unifiedDiffJSON{}
+	if err := unmarshalSchema(data, schemaKindUnified, &decoded); err != nil {
+		return err
+	}
+	*d = UnifiedDiff(decoded)
+	return nil
+}
+
+// MarshalJSON encodes the reports as versioned document of kind "bits", see SchemaVersion.
+func (r BitReports) MarshalJSON() ([]byte, error) {
+	reports := make([]bitReportJSON, len(r))
+	for i, report := range r {
+		encoded, err := encodeReport(report.Report)
+		if err != nil {
+			return nil, fmt.Errorf("report %d: %w", i, err)
+		}
+		reports[i] = bitReportJSON{reportJSON: encoded, Order: report.Order, Mask: report.Mask, Bits: report.Bits}
+	}
+	return marshalSchema(schemaKindBits, reports)
+}
+
+// UnmarshalJSON decodes reports encoded by MarshalJSON.
+func (r *BitReports) UnmarshalJSON(data []byte) error {
+	encoded := []bitReportJSON{}
+	if err := unmarshalSchema(data, schemaKindBits, &encoded); err != nil {
+		return err
+	}
+	reports := make(BitReports, len(encoded))
+	for i, report := range encoded {
+		decoded, err := report.decode()
+		if err != nil {
+			return fmt.Errorf("report %d: %w", i, err)
+		}
+		reports[i] = BitReport{Report: decoded, Order: report.Order, Mask: report.Mask, Bits: report.Bits}
+	}
+	*r = reports
+	return nil
+}
+
+// isJSONArray reports whether the JSON value is an array, as in the version 0 encoding.
+func isJSONArray(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// marshalSchema encodes the reports in the versioned envelope.
+func marshalSchema(kind string, reports interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(reports)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schemaDocument{Version: SchemaVersion, Kind: kind, Reports: encoded})
+}
+
+// unmarshalSchema checks the envelope and decodes the reports into v.
+func unmarshalSchema(data []byte, kind string, v interface{}) error {
+	doc := schemaDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if doc.Version != SchemaVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, doc.Version)
+	}
+	if doc.Kind != kind {
+		return fmt.Errorf("%w: kind %q, want %q", ErrInvalidSchema, doc.Kind, kind)
+	}
+	if err := json.Unmarshal(doc.Reports, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return nil
+}
+
+// reportsCSVHeader defines the columns of the CSV encoding of Reports.
+var reportsCSVHeader = []string{"type", "kind", "index", "original", "new"}
+
+// WriteCSV writes the reports as CSV with the header "type,kind,index,original,new".
+// The columns follow the JSON encoding, null values are written as empty fields.
+func (r Reports) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportsCSVHeader); err != nil {
+		return err
+	}
+	for i, report := range r {
+		encoded, err := encodeReport(report)
+		if err != nil {
+			return fmt.Errorf("report %d: %w", i, err)
+		}
+		if err := writer.Write([]string{report.Type, report.Kind.String(), strconv.Itoa(report.Index), formatCSVByte(encoded.Original), formatCSVByte(encoded.New)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadReportsCSV reads reports written by Reports.WriteCSV.
+func ReadReportsCSV(r io.Reader) (Reports, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(reportsCSVHeader)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if len(records) == 0 || fmt.Sprint(records[0]) != fmt.Sprint(reportsCSVHeader) {
+		return nil, fmt.Errorf("%w: missing csv header", ErrInvalidSchema)
+	}
+	reports := make(Reports, 0, len(records)-1)
+	for i, record := range records[1:] {
+		encoded := reportJSON{Type: record[0]}
+		if err := encoded.Kind.UnmarshalText([]byte(record[1])); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if encoded.Index, err = strconv.Atoi(record[2]); err != nil {
+			return nil, fmt.Errorf("row %d: %w: index %q", i+1, ErrInvalidSchema, record[2])
+		}
+		if encoded.Original, err = parseCSVByte(record[3]); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if encoded.New, err = parseCSVByte(record[4]); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		report, err := encoded.decode()
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// formatCSVByte formats a byte value, nil is an empty field.
+func formatCSVByte(b *byte) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*b))
+}
+
+// parseCSVByte parses a byte value, an empty field is nil.
+func parseCSVByte(field string) (*byte, error) {
+	if field == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(field, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: byte %q", ErrInvalidSchema, field)
+	}
+	b := byte(value)
+	return &b, nil
+}
+
+// valueReportsCSVHeader defines the columns of the CSV encoding of ValueReports.
+var valueReportsCSVHeader = []string{"path", "kind", "old", "new"}
+
+// WriteCSV writes the reports as CSV with the header "path,kind,old,new".
+// old and new are JSON encoded, they are empty for inserted and deleted values respectively.
+func (r ValueReports) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(valueReportsCSVHeader); err != nil {
+		return err
+	}
+	for _, report := range r {
+		kind, err := report.Kind.MarshalText()
+		if err != nil {
+			return err
+		}
+		old, new := "", ""
+		if report.Kind != ReportKindInserted {
+			encoded, err := encodeJSON(report.Old)
+			if err != nil {
+				return err
+			}
+			old = string(encoded)
+		}
+		if report.Kind != ReportKindDeleted {
+			encoded, err := encodeJSON(report.New)
+			if err != nil {
+				return err
+			}
+			new = string(encoded)
+		}
+		if err := writer.Write([]string{report.Path, string(kind), old, new}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadValueReportsCSV reads reports written by ValueReports.WriteCSV.
+// Values are decoded like the values of ValueReports.UnmarshalJSON.
+func ReadValueReportsCSV(r io.Reader) (ValueReports, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(valueReportsCSVHeader)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if len(records) == 0 || fmt.Sprint(records[0]) != fmt.Sprint(valueReportsCSVHeader) {
+		return nil, fmt.Errorf("%w: missing csv header", ErrInvalidSchema)
+	}
+	reports := make(ValueReports, 0, len(records)-1)
+	for i, record := range records[1:] {
+		encoded := valueReportJSON{Path: record[0]}
+		if err := encoded.Kind.UnmarshalText([]byte(record[1])); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if record[2] != "" {
+			encoded.Old = json.RawMessage(record[2])
+		}
+		if record[3] != "" {
+			encoded.New = json.RawMessage(record[3])
+		}
+		report, err := encoded.decode()
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,480 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReports_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports Reports
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			reports: Reports{},
+			want:    `{"version":1,"kind":"bytes","reports":[]}`,
+		},
+		{
+			name: "all kinds",
+			reports: Reports{
+				{Type: "uint8", Kind: ReportKindChanged, Index: 0, Original: bytePtr('a'), New: 'b'},
+				{Type: "uint8", Kind: ReportKindDeleted, Index: 2, Original: bytePtr(0)},
+				{Type: "uint8", Kind: ReportKindInserted, Index: 3, New: 0},
+			},
+			want: `{"version":1,"kind":"bytes","reports":[` +
+				`{"type":"uint8","kind":"changed","index":0,"original":97,"new":98},` +
+				`{"type":"uint8","kind":"deleted","index":2,"original":0,"new":null},` +
+				`{"type":"uint8","kind":"inserted","index":3,"original":null,"new":0}]}`,
+		},
+		{
+			name:    "changed without original",
+			reports: Reports{{Kind: ReportKindChanged, New: 'b'}},
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "equal",
+			reports: Reports{{Kind: ReportKindEqual, Original: bytePtr('a'), New: 'a'}},
+			wantErr: ErrInvalidSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.reports)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("json.Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReports_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Reports
+		wantErr error
+	}{
+		{
+			name: "all kinds",
+			data: `{"version":1,"kind":"bytes","reports":[` +
+				`{"type":"uint8","kind":"changed","index":0,"original":97,"new":98},` +
+				`{"type":"uint8","kind":"deleted","index":2,"original":0,"new":null},` +
+				`{"type":"uint8","kind":"inserted","index":3,"original":null,"new":0}]}`,
+			want: Reports{
+				{Type: "uint8", Kind: ReportKindChanged, Index: 0, Original: bytePtr('a'), New: 'b'},
+				{Type: "uint8", Kind: ReportKindDeleted, Index: 2, Original: bytePtr(0)},
+				{Type: "uint8", Kind: ReportKindInserted, Index: 3, New: 0},
+			},
+		},
+		{
+			name:    "unsupported version",
+			data:    `{"version":2,"kind":"bytes","reports":[]}`,
+			wantErr: ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:    "missing version",
+			data:    `{"kind":"bytes","reports":[]}`,
+			wantErr: ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:    "wrong kind",
+			data:    `{"version":1,"kind":"values","reports":[]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "unknown report kind",
+			data:    `{"version":1,"kind":"bytes","reports":[{"type":"uint8","kind":"moved","index":0,"original":1,"new":2}]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "inserted with original",
+			data:    `{"version":1,"kind":"bytes","reports":[{"type":"uint8","kind":"inserted","index":0,"original":1,"new":2}]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "deleted with new",
+			data:    `{"version":1,"kind":"bytes","reports":[{"type":"uint8","kind":"deleted","index":0,"original":1,"new":2}]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "byte out of range",
+			data:    `{"version":1,"kind":"bytes","reports":[{"type":"uint8","kind":"changed","index":0,"original":256,"new":2}]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "negative index",
+			data:    `{"version":1,"kind":"bytes","reports":[{"type":"uint8","kind":"changed","index":-1,"original":1,"new":2}]}`,
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "reports not an array",
+			data:    `{"version":1,"kind":"bytes","reports":{}}`,
+			wantErr: ErrInvalidSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Reports{}
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("json.Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReports_UnmarshalJSON_version0(t *testing.T) {
+	// baselineReport is the Report struct of the release before the versioned encoding
+	type baselineReport struct {
+		Type     string
+		Index    int
+		Original *byte
+		New      byte
+	}
+	data, err := json.Marshal([]baselineReport{
+		{Type: "uint8", Index: 0, Original: bytePtr('a'), New: 'b'},
+		{Type: "uint8", Index: 5, Original: nil, New: 6},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Reports{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", data, err)
+	}
+	want := Reports{
+		{Type: "uint8", Kind: ReportKindChanged, Index: 0, Original: bytePtr('a'), New: 'b'},
+		{Type: "uint8", Kind: ReportKindInserted, Index: 5, New: 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal(%s) = %v, want %v", data, got, want)
+	}
+
+	withKind := `[{"Type":"uint8","Kind":2,"Index":2,"Original":0,"New":0}]`
+	if err := json.Unmarshal([]byte(withKind), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", withKind, err)
+	}
+	if want := (Reports{{Type: "uint8", Kind: ReportKindDeleted, Index: 2, Original: bytePtr(0)}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal(%s) = %v, want %v", withKind, got, want)
+	}
+	unknown := `[{"Type":"uint8","Kind":7,"Index":0,"Original":97,"New":98}]`
+	if err := json.Unmarshal([]byte(unknown), &got); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("json.Unmarshal(%s) error = %v, want %v", unknown, err, ErrInvalidSchema)
+	}
+}
+
+func TestReports_RoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := make([]byte, random.Intn(32))
+		random.Read(a)
+		b := append([]byte{}, a...)
+		for k := random.Intn(5); k > 0 && len(b) > 0; k-- {
+			b[random.Intn(len(b))] = byte(random.Intn(256))
+		}
+		b = append(b, byte(random.Intn(256)))
+		reports := BytesDiff(a, b)
+
+		data, err := json.Marshal(reports)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		fromJSON := Reports{}
+		if err := json.Unmarshal(data, &fromJSON); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if !reflect.DeepEqual(fromJSON, reports) {
+			t.Fatalf("json round trip = %v, want %v", fromJSON, reports)
+		}
+
+		buf := bytes.Buffer{}
+		if err := reports.WriteCSV(&buf); err != nil {
+			t.Fatalf("WriteCSV() error = %v", err)
+		}
+		fromCSV, err := ReadReportsCSV(&buf)
+		if err != nil {
+			t.Fatalf("ReadReportsCSV() error = %v", err)
+		}
+		if !reflect.DeepEqual(fromCSV, reports) {
+			t.Fatalf("csv round trip = %v, want %v", fromCSV, reports)
+		}
+		if got, err := Apply(a, fromCSV); err != nil || !bytes.Equal(got, b) {
+			t.Fatalf("Apply() = %v, %v, want %v", got, err, b)
+		}
+	}
+}
+
+func TestReports_WriteCSV(t *testing.T) {
+	reports := Reports{
+		{Type: "uint8", Kind: ReportKindChanged, Index: 0, Original: bytePtr('a'), New: 'b'},
+		{Type: "uint8", Kind: ReportKindDeleted, Index: 2, Original: bytePtr(0)},
+		{Type: "uint8", Kind: ReportKindInserted, Index: 3, New: 10},
+	}
+	want := "type,kind,index,original,new\n" +
+		"uint8,changed,0,97,98\n" +
+		"uint8,deleted,2,0,\n" +
+		"uint8,inserted,3,,10\n"
+	buf := bytes.Buffer{}
+	if err := reports.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestReadReportsCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name: "header only",
+			data: "type,kind,index,original,new\n",
+		},
+		{
+			name:    "empty",
+			data:    "",
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "wrong header",
+			data:    "kind,type,index,original,new\n",
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "missing column",
+			data:    "type,kind,index,original,new\nuint8,changed,0,97\n",
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "invalid index",
+			data:    "type,kind,index,original,new\nuint8,changed,x,97,98\n",
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "invalid byte",
+			data:    "type,kind,index,original,new\nuint8,changed,0,300,98\n",
+			wantErr: ErrInvalidSchema,
+		},
+		{
+			name:    "changed without new",
+			data:    "type,kind,index,original,new\nuint8,changed,0,97,\n",
+			wantErr: ErrInvalidSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadReportsCSV(strings.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadReportsCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(got) != 0 {
+				t.Errorf("ReadReportsCSV() = %v, want no reports", got)
+			}
+		})
+	}
+}
+
+func TestValueReports_JSON(t *testing.T) {
+	reports := ValueReports{
+		{Path: "", Kind: ReportKindChanged, Old: nil, New: "a"},
+		{Path: "users[1]", Kind: ReportKindInserted, New: map[string]interface{}{"name": "b"}},
+		{Path: "total", Kind: ReportKindDeleted, Old: 3},
+		{Path: "tags[0]", Kind: ReportKindChanged, Old: "x", New: []string{"y"}},
+	}
+	data, err := json.Marshal(reports)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"version":1,"kind":"values","reports":[` +
+		`{"path":"","kind":"changed","old":null,"new":"a"},` +
+		`{"path":"users[1]","kind":"inserted","new":{"name":"b"}},` +
+		`{"path":"total","kind":"deleted","old":3},` +
+		`{"path":"tags[0]","kind":"changed","old":"x","new":["y"]}]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	got := ValueReports{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	wantReports := ValueReports{
+		{Path: "", Kind: ReportKindChanged, Old: nil, New: "a"},
+		{Path: "users[1]", Kind: ReportKindInserted, New: map[string]interface{}{"name": "b"}},
+		{Path: "total", Kind: ReportKindDeleted, Old: json.Number("3")},
+		{Path: "tags[0]", Kind: ReportKindChanged, Old: "x", New: []interface{}{"y"}},
+	}
+	if !reflect.DeepEqual(got, wantReports) {
+		t.Errorf("json.Unmarshal() = %#v, want %#v", got, wantReports)
+	}
+
+	for _, data := range []string{
+		`{"version":1,"kind":"values","reports":[{"path":"a","kind":"inserted","old":1,"new":2}]}`,
+		`{"version":1,"kind":"values","reports":[{"path":"a","kind":"changed","new":2}]}`,
+		`{"version":1,"kind":"bytes","reports":[]}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &got); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("json.Unmarshal(%s) error = %v, want %v", data, err, ErrInvalidSchema)
+		}
+	}
+}
+
+func TestValueReports_WriteCSV(t *testing.T) {
+	reports := ValueReports{
+		{Path: "name", Kind: ReportKindChanged, Old: "a", New: "b,c"},
+		{Path: "users[1]", Kind: ReportKindInserted, New: map[string]interface{}{"name": "b"}},
+		{Path: "total", Kind: ReportKindDeleted, Old: 3},
+	}
+	want := "path,kind,old,new\n" +
+		"name,changed,\"\"\"a\"\"\",\"\"\"b,c\"\"\"\n" +
+		"users[1],inserted,,\"{\"\"name\"\":\"\"b\"\"}\"\n" +
+		"total,deleted,3,\n"
+	buf := bytes.Buffer{}
+	if err := reports.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func bytePtr(b byte) *byte {
+	return &b
+}
+
+func TestSchema_kinds(t *testing.T) {
+	hunks, err := Reports{{Type: "uint8", Kind: ReportKindChanged, Index: 1, Original: bytePtr('b'), New: 'x'}}.Hunks([]byte("abc"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits, err := BitsDifferent([]byte("a"), []byte("c"), BitOrderMSB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		value   interface{}
+		decoded interface{}
+		want    string
+	}{
+		{
+			name:    "sequence",
+			value:   SequenceReports{{Type: "string", Kind: ReportKindChanged, Index: 1, Original: "b", New: "x"}, {Type: "string", Kind: ReportKindDeleted, Index: 2, Original: "c"}},
+			decoded: &SequenceReports{},
+			want: `{"version":1,"kind":"sequence","reports":[` +
+				`{"type":"string","kind":"changed","index":1,"original":"b","new":"x"},` +
+				`{"type":"string","kind":"deleted","index":2,"original":"c"}]}`,
+		},
+		{
+			name:    "text",
+			value:   DiffText("ab", "ax"),
+			decoded: &TextReports{},
+			want: `{"version":1,"kind":"text","reports":[{"kind":"changed","original":"b","new":"x",` +
+				`"originalPosition":{"byteOffset":1,"runeOffset":1,"line":1,"column":2},` +
+				`"newPosition":{"byteOffset":1,"runeOffset":1,"line":1,"column":2}}]}`,
+		},
+		{
+			name:    "segments",
+			value:   DiffWords("a b", "a c"),
+			decoded: &Segments{},
+			want: `{"version":1,"kind":"segments","reports":[{"kind":"equal","text":"a "},` +
+				`{"kind":"deleted","text":"b"},{"kind":"inserted","text":"c"}]}`,
+		},
+		{
+			name:    "hunks",
+			value:   hunks,
+			decoded: &Hunks{},
+			want: `{"version":1,"kind":"hunks","reports":[{"oldStart":0,"oldEnd":3,"newStart":0,"newEnd":3,` +
+				`"old":[97,98,99],"new":[97,120,99],"changed":1,"inserted":0,"deleted":0}]}`,
+		},
+		{
+			name:    "unified",
+			value:   NewUnifiedDiff("a\n", "b", UnifiedOptions{FromFile: "a", ToFile: "b"}),
+			decoded: &UnifiedDiff{},
+			want: `{"version":1,"kind":"unified","reports":{"fromFile":"a","toFile":"b","hunks":[` +
+				`{"fromLine":1,"fromCount":1,"toLine":1,"toCount":1,"lines":[` +
+				`{"op":"delete","text":"a"},{"op":"insert","text":"b","noNewline":true}]}]}}`,
+		},
+		{
+			name:    "bits",
+			value:   bits,
+			decoded: &BitReports{},
+			want: `{"version":1,"kind":"bits","reports":[{"type":"uint8","kind":"changed","index":0,` +
+				`"original":97,"new":99,"order":"msb","mask":2,"bits":[6]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+			if err := json.Unmarshal(data, tt.decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got := reflect.ValueOf(tt.decoded).Elem().Interface(); !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("json.Unmarshal() = %#v, want %#v", got, tt.value)
+			}
+			if err := json.Unmarshal([]byte(`{"version":1,"kind":"other","reports":[]}`), tt.decoded); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("json.Unmarshal(other kind) error = %v, want %v", err, ErrInvalidSchema)
+			}
+		})
+	}
+}
+
+func TestValueReports_CSVRoundTrip(t *testing.T) {
+	reports := ValueReports{
+		{Path: "name", Kind: ReportKindChanged, Old: "a", New: "b,c"},
+		{Path: "", Kind: ReportKindChanged, Old: nil, New: []interface{}{"x", json.Number("1")}},
+		{Path: "users[1]", Kind: ReportKindInserted, New: map[string]interface{}{"name": "b"}},
+		{Path: "total", Kind: ReportKindDeleted, Old: json.Number("3")},
+	}
+	buf := bytes.Buffer{}
+	if err := reports.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	got, err := ReadValueReportsCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadValueReportsCSV() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, reports) {
+		t.Errorf("csv round trip = %#v, want %#v", got, reports)
+	}
+
+	for _, data := range []string{
+		"",
+		"path,kind,old,new\nname,moved,1,2\n",
+		"path,kind,old,new\nname,inserted,1,2\n",
+		"path,kind,old,new\nname,changed,{,2\n",
+	} {
+		if _, err := ReadValueReportsCSV(strings.NewReader(data)); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("ReadValueReportsCSV(%q) error = %v, want %v", data, err, ErrInvalidSchema)
+		}
+	}
+}
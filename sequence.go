@@ -0,0 +1,163 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotASequence is returned when a value is neither a slice, an array nor a string.
+	ErrNotASequence = errors.New("value is not a sequence")
+)
+
+// DiffSequence returns the shortest edit script that transforms a sequence A of length n
+// into a sequence B of length m. equal reports whether the i-th element of A equals
+// the j-th element of B, so the sequences may have any element type.
+func DiffSequence(n, m int, equal func(i, j int) bool) EditScript {
+	return myersDiff(n, m, equal)
+}
+
+// SequenceReports defines a list of differences between two sequences.
+type SequenceReports []SequenceReport
+
+// SequenceReport describes a difference between two sequences of any element type.
+type SequenceReport struct {
+	// Type defines the element type of A and B
+	// This field has no functional purpose and only exists for discovery.
+	Type string
+	// Kind defines whether the element was changed, inserted or deleted.
+	Kind ReportKind
+	// Index is the index of the element that differs
+	// For inserted elements the index refers to B, otherwise it refers to A.
+	Index int
+	// Original represents the element at index in A
+	// Original is nil if the element was inserted.
+	Original interface{}
+	// New represents the element of B that replaces or follows the original element.
+	// New is nil if the element was deleted.
+	New interface{}
+}
+
+// DiffSlices returns the difference between the sequences a and b.
+// a and b must be slices, arrays or strings (compared rune by rune).
+// equal compares two elements, if nil reflect.DeepEqual is used.
+func DiffSlices(a, b interface{}, equal func(x, y interface{}) bool) (SequenceReports, error) {
+	av, err := sequenceValue(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, err := sequenceValue(b)
+	if err != nil {
+		return nil, err
+	}
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+	aElems, bElems := sequenceElements(av), sequenceElements(bv)
+	script := DiffSequence(len(aElems), len(bElems), func(i, j int) bool {
+		return equal(aElems[i], bElems[j])
+	})
+	return script.SequenceReports(aElems, bElems), nil
+}
+
+// DiffStrings returns the difference between the string slices a and b, e.g. lines or tokens.
+func DiffStrings(a, b []string) SequenceReports {
+	aElems, bElems := make([]interface{}, len(a)), make([]interface{}, len(b))
+	for i := range a {
+		aElems[i] = a[i]
+	}
+	for i := range b {
+		bElems[i] = b[i]
+	}
+	script := DiffSequence(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+	return script.SequenceReports(aElems, bElems)
+}
+
+// SequenceReports converts the edit script of the elements a and b into reports.
+// Equal runs are omitted. Deleted elements that are directly followed by inserted
+// elements are reported as changed.
+func (s EditScript) SequenceReports(a, b []interface{}) SequenceReports {
+	reports := SequenceReports{}
+	s.walkReports(func(kind ReportKind, i, j int) {
+		report := SequenceReport{Kind: kind}
+		switch kind {
+		case ReportKindChanged:
+			report.Index, report.Original, report.New = i, a[i], b[j]
+		case ReportKindDeleted:
+			report.Index, report.Original = i, a[i]
+		case ReportKindInserted:
+			report.Index, report.New = j, b[j]
+		}
+		report.Type = elementType(report.Original, report.New)
+		reports = append(reports, report)
+	})
+	return reports
+}
+
+// walkReports calls fn for every changed, deleted and inserted element of the edit script.
+// i is the index in A and j the index in B. For deleted elements j and for inserted
+// elements i is the position the element would have in the other sequence.
+func (s EditScript) walkReports(fn func(kind ReportKind, i, j int)) {
+	for idx := 0; idx < len(s); idx++ {
+		edit := s[idx]
+		switch edit.Op {
+		case EditDelete:
+			inserted := Edit{Op: EditInsert, AStart: edit.AEnd, AEnd: edit.AEnd, BStart: edit.BEnd, BEnd: edit.BEnd}
+			if idx+1 < len(s) && s[idx+1].Op == EditInsert {
+				inserted = s[idx+1]
+				idx++
+			}
+			changed := edit.AEnd - edit.AStart
+			if n := inserted.BEnd - inserted.BStart; n < changed {
+				changed = n
+			}
+			for k := 0; k < changed; k++ {
+				fn(ReportKindChanged, edit.AStart+k, inserted.BStart+k)
+			}
+			for k := edit.AStart + changed; k < edit.AEnd; k++ {
+				fn(ReportKindDeleted, k, inserted.BStart+changed)
+			}
+			for k := inserted.BStart + changed; k < inserted.BEnd; k++ {
+				fn(ReportKindInserted, edit.AEnd, k)
+			}
+		case EditInsert:
+			for k := edit.BStart; k < edit.BEnd; k++ {
+				fn(ReportKindInserted, edit.AStart, k)
+			}
+		}
+	}
+}
+
+// sequenceValue returns the reflect value of a slice, array or string.
+func sequenceValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv, nil
+	case reflect.String:
+		return reflect.ValueOf([]rune(rv.String())), nil
+	}
+	return reflect.Value{}, fmt.Errorf("%w: %T", ErrNotASequence, v)
+}
+
+// sequenceElements returns the elements of the sequence.
+func sequenceElements(v reflect.Value) []interface{} {
+	elems := make([]interface{}, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i).Interface()
+	}
+	return elems
+}
+
+// elementType returns the type name of the first non-nil value.
+func elementType(values ...interface{}) string {
+	for _, v := range values {
+		if v != nil {
+			return reflect.TypeOf(v).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package compare
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sequenceTestItem struct {
+	ID   int
+	Name string
+}
+
+func TestDiffSlices(t *testing.T) {
+	type args struct {
+		a     interface{}
+		b     interface{}
+		equal func(x, y interface{}) bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    SequenceReports
+		wantErr bool
+	}{
+		{
+			name: "equal string slices",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"a", "b"},
+			},
+			want:    SequenceReports{},
+			wantErr: false,
+		},
+		{
+			name: "inserted line",
+			args: args{
+				a: []string{"first", "third"},
+				b: []string{"first", "second", "third"},
+			},
+			want: SequenceReports{
+				{Type: "string", Kind: ReportKindInserted, Index: 1, Original: nil, New: "second"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "runes of strings",
+			args: args{
+				a: "grün",
+				b: "grün!",
+			},
+			want: SequenceReports{
+				{Type: "int32", Kind: ReportKindInserted, Index: 4, Original: nil, New: '!'},
+			},
+			wantErr: false,
+		},
+		{
+			name: "structs with deep equal",
+			args: args{
+				a: []sequenceTestItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+				b: []sequenceTestItem{{ID: 1, Name: "a"}, {ID: 2, Name: "c"}},
+			},
+			want: SequenceReports{
+				{
+					Type:     "compare.sequenceTestItem",
+					Kind:     ReportKindChanged,
+					Index:    1,
+					Original: sequenceTestItem{ID: 2, Name: "b"},
+					New:      sequenceTestItem{ID: 2, Name: "c"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "structs with custom equal",
+			args: args{
+				a: []sequenceTestItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+				b: []sequenceTestItem{{ID: 2, Name: "c"}},
+				equal: func(x, y interface{}) bool {
+					return x.(sequenceTestItem).ID == y.(sequenceTestItem).ID
+				},
+			},
+			want: SequenceReports{
+				{
+					Type:     "compare.sequenceTestItem",
+					Kind:     ReportKindDeleted,
+					Index:    0,
+					Original: sequenceTestItem{ID: 1, Name: "a"},
+					New:      nil,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "case-insensitive tokens",
+			args: args{
+				a: [3]string{"GET", "/index", "HTTP/1.1"},
+				b: []string{"get", "/home", "http/1.1"},
+				equal: func(x, y interface{}) bool {
+					return strings.EqualFold(x.(string), y.(string))
+				},
+			},
+			want: SequenceReports{
+				{Type: "string", Kind: ReportKindChanged, Index: 1, Original: "/index", New: "/home"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "not a sequence",
+			args: args{
+				a: 42,
+				b: []int{42},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffSlices(tt.args.a, tt.args.b, tt.args.equal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DiffSlices() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSlices() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffStrings(t *testing.T) {
+	got := DiffStrings([]string{"a", "b", "c"}, []string{"x", "a", "c"})
+	want := SequenceReports{
+		{Type: "string", Kind: ReportKindInserted, Index: 0, Original: nil, New: "x"},
+		{Type: "string", Kind: ReportKindDeleted, Index: 1, Original: "b", New: nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffStrings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffSequence(t *testing.T) {
+	a := []float64{1.0, 2.0, 3.0}
+	b := []float64{1.01, 2.5, 3.0}
+	got := DiffSequence(len(a), len(b), func(i, j int) bool {
+		diff := a[i] - b[j]
+		return diff < 0.1 && diff > -0.1
+	})
+	want := EditScript{
+		{Op: EditEqual, AStart: 0, AEnd: 1, BStart: 0, BEnd: 1},
+		{Op: EditDelete, AStart: 1, AEnd: 2, BStart: 1, BEnd: 1},
+		{Op: EditInsert, AStart: 2, AEnd: 2, BStart: 1, BEnd: 2},
+		{Op: EditEqual, AStart: 2, AEnd: 3, BStart: 2, BEnd: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffSequence() = %+v, want %+v", got, want)
+	}
+}
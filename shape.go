@@ -0,0 +1,204 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrEmptyShape is returned when a struct shape has no non-zero exported field and would compare nothing.
+	ErrEmptyShape = errors.New("shape compares no fields")
+)
+
+// matchShape reports whether actual has the shape of expected.
+// Only the keys listed in expected are compared, additional keys in actual are ignored:
+// - maps: every key of expected must exist in actual and match recursively
+// - structs: every non-zero exported field of expected must exist in actual and match recursively
+// - slices and arrays: actual must have the same length and every element must match recursively
+// - Validation: the validation is executed against the actual value
+// - any other value: the values must be deeply equal, numbers are compared by value
+// Structs without exported fields (e.g. time.Time) are leaves and must be deeply equal.
+// Zero fields of structs are not compared, use pointers or Validation leaves to compare zero values,
+// e.g. a *bool pointing to false. A struct without non-zero exported fields returns ErrEmptyShape.
+// Maps and structs are interchangeable, struct fields are looked up by their json name or field name.
+// Nested validations are evaluated with the options of the shape validation.
+func matchShape(expected, actual interface{}, opts MatchOptions) (bool, error) {
+	return matchShapeValue("", expected, actual, opts)
+}
+
+// matchShapeValue matches expected against actual. The path is used for error messages only.
+func matchShapeValue(path string, expected, actual interface{}, opts MatchOptions) (bool, error) {
+	switch v := expected.(type) {
+	case Validation:
+		ok, err := v.MatchesWithOptions(actual, opts)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", shapePath(path), err)
+		}
+		return ok, nil
+	case *Validation:
+		if v != nil {
+			return matchShapeValue(path, *v, actual, opts)
+		}
+	}
+
+	ev := indirectValue(reflect.ValueOf(expected))
+	av := indirectValue(reflect.ValueOf(actual))
+	if !ev.IsValid() || !av.IsValid() {
+		return ev.IsValid() == av.IsValid(), nil
+	}
+
+	switch ev.Kind() {
+	case reflect.Map:
+		if av.Kind() != reflect.Map && av.Kind() != reflect.Struct {
+			return false, nil
+		}
+		iter := ev.MapRange()
+		for iter.Next() {
+			child, ok := shapeChild(av, iter.Key())
+			if !ok {
+				return false, nil
+			}
+			ok, err := matchShapeValue(path+"."+fmt.Sprint(iter.Key().Interface()), iter.Value().Interface(), child.Interface(), opts)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case reflect.Struct:
+		if !hasExportedFields(ev.Type()) || (av.Kind() != reflect.Map && av.Kind() != reflect.Struct) {
+			return reflect.DeepEqual(ev.Interface(), av.Interface()), nil
+		}
+		compared := 0
+		for i := 0; i < ev.NumField(); i++ {
+			field := ev.Type().Field(i)
+			if field.PkgPath != "" || ev.Field(i).IsZero() {
+				continue
+			}
+			compared++
+			child, ok := shapeChild(av, reflect.ValueOf(jsonFieldName(field)))
+			if !ok {
+				child, ok = shapeChild(av, reflect.ValueOf(field.Name))
+			}
+			if !ok {
+				return false, nil
+			}
+			ok, err := matchShapeValue(path+"."+field.Name, ev.Field(i).Interface(), child.Interface(), opts)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		if compared == 0 {
+			return false, fmt.Errorf("%s: %w: all exported fields of %s are zero", shapePath(path), ErrEmptyShape, ev.Type())
+		}
+		return true, nil
+	case reflect.Slice, reflect.Array:
+		if av.Kind() != reflect.Slice && av.Kind() != reflect.Array {
+			return false, nil
+		}
+		if ev.Len() != av.Len() {
+			return false, nil
+		}
+		for i := 0; i < ev.Len(); i++ {
+			ok, err := matchShapeValue(fmt.Sprintf("%s[%d]", path, i), ev.Index(i).Interface(), av.Index(i).Interface(), opts)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	if ef, ok := numberToFloat64(ev); ok {
+		af, ok := numberToFloat64(av)
+		return ok && ef == af, nil
+	}
+	return reflect.DeepEqual(ev.Interface(), av.Interface()), nil
+}
+
+// shapeChild returns the value of the map key or struct field named by key.
+func shapeChild(v reflect.Value, key reflect.Value) (reflect.Value, bool) {
+	key = indirectValue(key)
+	switch v.Kind() {
+	case reflect.Map:
+		switch {
+		case key.Type().AssignableTo(v.Type().Key()):
+		case key.Type().ConvertibleTo(v.Type().Key()) && key.Kind() == v.Type().Key().Kind():
+			key = key.Convert(v.Type().Key())
+		default:
+			return reflect.Value{}, false
+		}
+		child := v.MapIndex(key)
+		return child, child.IsValid()
+	case reflect.Struct:
+		if key.Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		name := key.String()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if field.Name == name || jsonFieldName(field) == name {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// hasExportedFields reports whether the struct type has at least one exported field.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldName returns the name of the field as used by encoding/json.
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		tag = tag[:idx]
+	}
+	if tag == "" || tag == "-" {
+		return field.Name
+	}
+	return tag
+}
+
+// indirectValue dereferences pointers and interfaces until a concrete value is reached.
+// Nil pointers and interfaces result in an invalid value.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// numberToFloat64 returns the value as float64 if it is a number.
+func numberToFloat64(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// shapePath returns a printable representation of the path.
+func shapePath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return "$" + path
+}
@@ -0,0 +1,290 @@
+package compare
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type shapeTestUser struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Count    int64             `json:"count"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Created  time.Time         `json:"created"`
+	internal string
+}
+
+func Test_matchShape(t *testing.T) {
+	decode := func(in string) interface{} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Fatalf("failed to decode %q: %v", in, err)
+		}
+		return v
+	}
+	created := time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		expected interface{}
+		actual   interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "partial map ignores additional keys",
+			args: args{
+				expected: map[string]interface{}{"name": "foo"},
+				actual:   decode(`{"id":"1","name":"foo","count":2}`),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "partial map with different value",
+			args: args{
+				expected: map[string]interface{}{"name": "bar"},
+				actual:   decode(`{"id":"1","name":"foo","count":2}`),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "partial map with missing key",
+			args: args{
+				expected: map[string]interface{}{"email": "foo@example.com"},
+				actual:   decode(`{"id":"1","name":"foo"}`),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "nested partial map",
+			args: args{
+				expected: map[string]interface{}{
+					"meta": map[string]interface{}{"version": 2},
+				},
+				actual: decode(`{"meta":{"version":2,"build":"abc"},"data":[]}`),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "slices are compared element by element",
+			args: args{
+				expected: map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": 1},
+						map[string]interface{}{"id": 2},
+					},
+				},
+				actual: decode(`{"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "slices with different length",
+			args: args{
+				expected: map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": 1},
+					},
+				},
+				actual: decode(`{"items":[{"id":1},{"id":2}]}`),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "nested validations as leaves",
+			args: args{
+				expected: map[string]interface{}{
+					"id":    Validation{MatchType: MatchTypeNotEmpty},
+					"count": Validation{MatchType: MatchTypeGreaterThan, ExpectedValue: int64(0)},
+				},
+				actual: shapeTestUser{ID: "1", Count: 3},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "nested validation does not match",
+			args: args{
+				expected: map[string]interface{}{
+					"count": &Validation{MatchType: MatchTypeGreaterThan, ExpectedValue: int64(5)},
+				},
+				actual: &shapeTestUser{ID: "1", Count: 3},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "nested validation returns error",
+			args: args{
+				expected: map[string]interface{}{
+					"name": Validation{MatchType: MatchTypeGreaterThan, ExpectedValue: int64(5)},
+				},
+				actual: shapeTestUser{Name: "foo"},
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "partial struct against decoded json",
+			args: args{
+				expected: shapeTestUser{Name: "foo", Count: 2},
+				actual:   decode(`{"id":"1","name":"foo","count":2,"tags":["a"]}`),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "partial struct against struct",
+			args: args{
+				expected: shapeTestUser{Labels: map[string]string{"env": "prod"}, Created: created},
+				actual: shapeTestUser{
+					ID:       "1",
+					Labels:   map[string]string{"env": "prod", "team": "a"},
+					Created:  created,
+					internal: "ignored",
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "struct without exported fields is a leaf",
+			args: args{
+				expected: shapeTestUser{Created: created},
+				actual:   shapeTestUser{Created: created.Add(time.Second)},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "nil expected and nil actual",
+			args: args{
+				expected: map[string]interface{}{"parent": nil},
+				actual:   decode(`{"parent":null}`),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "map expected against scalar",
+			args: args{
+				expected: map[string]interface{}{"id": 1},
+				actual:   "foo",
+			},
+			want:    false,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchShape(tt.args.expected, tt.args.actual, MatchOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("matchShape() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("matchShape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidation_MatchesWithOptions_shape(t *testing.T) {
+	shape := Validation{
+		MatchType: MatchTypeShape,
+		ExpectedValue: map[string]interface{}{
+			"name": Validation{MatchType: MatchTypeEqual, ExpectedValue: "alice"},
+		},
+	}
+	value := map[string]interface{}{"name": "  Alice "}
+	got, err := shape.MatchesWithOptions(value, MatchOptions{Normalizers: Normalizers{NormalizeTrimSpace, NormalizeCaseFold}})
+	if err != nil || !got {
+		t.Errorf("Validation.MatchesWithOptions() = %v, %v, want true, nil", got, err)
+	}
+	if got, err := shape.Matches(value); err != nil || got {
+		t.Errorf("Validation.Matches() = %v, %v, want false, nil", got, err)
+	}
+}
+
+func TestValidation_Matches_shapeDecodedJSON(t *testing.T) {
+	shape := Validation{
+		MatchType: MatchTypeShape,
+		ExpectedValue: map[string]interface{}{
+			"id":    Validation{MatchType: MatchTypeNotEmpty},
+			"count": Validation{MatchType: MatchTypeGreaterThan, ExpectedValue: int64(0)},
+		},
+	}
+	tests := []struct {
+		name      string
+		body      string
+		useNumber bool
+		want      bool
+		wantErr   bool
+	}{
+		{name: "float64 count", body: `{"id":"a","count":3}`, want: true},
+		{name: "json.Number count", body: `{"id":"a","count":3}`, useNumber: true, want: true},
+		{name: "zero count", body: `{"id":"a","count":0}`, want: false},
+		{name: "fractional count", body: `{"id":"a","count":2.5}`, want: true},
+		{name: "fractional json.Number count", body: `{"id":"a","count":0.01}`, useNumber: true, want: true},
+		{name: "negative fractional count", body: `{"id":"a","count":-0.5}`, want: false},
+		{name: "string count", body: `{"id":"a","count":"3"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tt.body))
+			if tt.useNumber {
+				decoder.UseNumber()
+			}
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				t.Fatal(err)
+			}
+			got, err := shape.Matches(value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validation.Matches() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Validation.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchShape_zeroFields(t *testing.T) {
+	type settings struct {
+		Enabled bool  `json:"enabled"`
+		Count   int64 `json:"count"`
+	}
+	type pointerSettings struct {
+		Enabled *bool `json:"enabled"`
+	}
+	disabled := false
+	actual := map[string]interface{}{"enabled": true, "count": 3}
+
+	if got, err := matchShape(settings{Enabled: false, Count: 0}, actual, MatchOptions{}); !errors.Is(err, ErrEmptyShape) || got {
+		t.Errorf("matchShape(zero struct) = %v, %v, want false, %v", got, err, ErrEmptyShape)
+	}
+	if got, err := matchShape([]settings{{}}, []interface{}{actual}, MatchOptions{}); !errors.Is(err, ErrEmptyShape) || got {
+		t.Errorf("matchShape(nested zero struct) = %v, %v, want false, %v", got, err, ErrEmptyShape)
+	}
+	if got, err := matchShape(pointerSettings{Enabled: &disabled}, actual, MatchOptions{}); err != nil || got {
+		t.Errorf("matchShape(pointer to false) = %v, %v, want false, nil", got, err)
+	}
+	if got, err := matchShape(settings{Count: 3}, actual, MatchOptions{}); err != nil || !got {
+		t.Errorf("matchShape(non-zero field) = %v, %v, want true, nil", got, err)
+	}
+}
@@ -0,0 +1,201 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrLengthMismatch is returned when a distance is only defined for data of equal length.
+	ErrLengthMismatch = errors.New("a and b differ in length")
+)
+
+// Stats summarizes the difference between the original and the new data.
+type Stats struct {
+	// Inserted defines the number of inserted elements.
+	Inserted int
+	// Deleted defines the number of deleted elements.
+	Deleted int
+	// Changed defines the number of changed elements.
+	Changed int
+	// Unchanged defines the number of elements that exist unchanged in both.
+	Unchanged int
+	// OldLength defines the length of the original data.
+	OldLength int
+	// NewLength defines the length of the new data.
+	NewLength int
+}
+
+// BytesStats returns the statistics of the difference between a and b based on BytesDiff.
+func BytesStats(a, b []byte) Stats {
+	return BytesDiff(a, b).Stats(len(a))
+}
+
+// Stats returns the statistics of the reports. oldLength is the length of the original data.
+func (r Reports) Stats(oldLength int) Stats {
+	stats := Stats{OldLength: oldLength}
+	for _, report := range r {
+		switch report.Kind {
+		case ReportKindChanged:
+			stats.Changed++
+		case ReportKindInserted:
+			stats.Inserted++
+		case ReportKindDeleted:
+			stats.Deleted++
+		}
+	}
+	stats.Unchanged = oldLength - stats.Changed - stats.Deleted
+	stats.NewLength = oldLength - stats.Deleted + stats.Inserted
+	return stats
+}
+
+// Different returns the number of inserted, deleted and changed elements.
+func (s Stats) Different() int {
+	return s.Inserted + s.Deleted + s.Changed
+}
+
+// Similarity returns the similarity ratio in the range 0.0 to 1.0 like Python's
+// difflib.SequenceMatcher.ratio: twice the number of unchanged elements divided by the
+// total number of elements in both. Two empty inputs are completely similar.
+func (s Stats) Similarity() float64 {
+	total := s.OldLength + s.NewLength
+	if total == 0 {
+		return 1.0
+	}
+	return 2 * float64(s.Unchanged) / float64(total)
+}
+
+// Deviation returns the share of different elements in the original data.
+// The result is capped at 100%, e.g. if more elements were inserted than originally existed.
+func (s Stats) Deviation() (*Percent, error) {
+	return newDeviationPercent(s.OldLength, s.Different())
+}
+
+// Insertions returns the number of insertions as counted by git: a changed element
+// is an insertion and a deletion.
+func (s Stats) Insertions() int {
+	return s.Inserted + s.Changed
+}
+
+// Deletions returns the number of deletions as counted by git: a changed element
+// is an insertion and a deletion.
+func (s Stats) Deletions() int {
+	return s.Deleted + s.Changed
+}
+
+// String returns the summary in the format of git diff --stat,
+// e.g. "3 insertions(+), 1 deletion(-)".
+func (s Stats) String() string {
+	parts := []string{}
+	if n := s.Insertions(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s(+)", n, plural(n, "insertion", "insertions")))
+	}
+	if n := s.Deletions(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s(-)", n, plural(n, "deletion", "deletions")))
+	}
+	if len(parts) == 0 {
+		return "0 insertions(+), 0 deletions(-)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// StatLine returns the line of git diff --stat for name, e.g. "config.yaml | 4 +++-".
+// The histogram is scaled down to at most width characters, but changes always keep at least
+// one character. A width of 0 or less disables scaling.
+func (s Stats) StatLine(name string, width int) string {
+	insertions, deletions := s.Insertions(), s.Deletions()
+	total := insertions + deletions
+	plus, minus := insertions, deletions
+	if width > 0 && total > width {
+		plus = scaleStat(insertions, total, width)
+		minus = scaleStat(deletions, total, width)
+		if plus+minus > width {
+			// rounding may exceed the width by one
+			if plus > minus {
+				plus--
+			} else {
+				minus--
+			}
+		}
+	}
+	line := name + " | " + strconv.Itoa(total)
+	if total > 0 {
+		line += " " + strings.Repeat("+", plus) + strings.Repeat("-", minus)
+	}
+	return line
+}
+
+// scaleStat scales n of total to width, keeping at least one character for non-zero values.
+func scaleStat(n, total, width int) int {
+	if n == 0 {
+		return 0
+	}
+	scaled := (n*width + total/2) / total
+	if scaled == 0 {
+		return 1
+	}
+	return scaled
+}
+
+// plural returns singular if n is 1, otherwise plural.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// Levenshtein returns the minimum number of inserted, deleted and substituted bytes
+// needed to transform a into b.
+func Levenshtein(a, b []byte) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	// row holds the distances of the previous prefix of a to all prefixes of b
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next := min3(row[j]+1, row[j-1]+1, diagonal+cost)
+			diagonal = row[j]
+			row[j] = next
+		}
+	}
+	return row[len(b)]
+}
+
+// Hamming returns the number of indexes at which a and b differ.
+// a and b must have the same length.
+func Hamming(a, b []byte) (int, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("%w: len(a)=%d, len(b)=%d", ErrLengthMismatch, len(a), len(b))
+	}
+	distance := 0
+	for i := range a {
+		if a[i] != b[i] {
+			distance++
+		}
+	}
+	return distance, nil
+}
+
+// min3 returns the smallest of a, b and c.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
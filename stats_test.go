@@ -0,0 +1,216 @@
+package compare
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBytesStats(t *testing.T) {
+	type args struct {
+		a []byte
+		b []byte
+	}
+	tests := []struct {
+		name           string
+		args           args
+		want           Stats
+		wantSimilarity float64
+		wantDeviation  float64
+	}{
+		{
+			name: "both empty",
+			args: args{
+				a: []byte{},
+				b: []byte{},
+			},
+			want:           Stats{},
+			wantSimilarity: 1.0,
+			wantDeviation:  0.0,
+		},
+		{
+			name: "equal",
+			args: args{
+				a: []byte("abcd"),
+				b: []byte("abcd"),
+			},
+			want:           Stats{Unchanged: 4, OldLength: 4, NewLength: 4},
+			wantSimilarity: 1.0,
+			wantDeviation:  0.0,
+		},
+		{
+			name: "changed and inserted",
+			args: args{
+				a: []byte("abcd"),
+				b: []byte("abxde"),
+			},
+			want:           Stats{Inserted: 1, Changed: 1, Unchanged: 3, OldLength: 4, NewLength: 5},
+			wantSimilarity: 6.0 / 9.0,
+			wantDeviation:  50.0,
+		},
+		{
+			name: "deleted",
+			args: args{
+				a: []byte("abcd"),
+				b: []byte("ad"),
+			},
+			want:           Stats{Deleted: 2, Unchanged: 2, OldLength: 4, NewLength: 2},
+			wantSimilarity: 4.0 / 6.0,
+			wantDeviation:  50.0,
+		},
+		{
+			name: "new data from nothing",
+			args: args{
+				a: []byte{},
+				b: []byte("ab"),
+			},
+			want:           Stats{Inserted: 2, NewLength: 2},
+			wantSimilarity: 0.0,
+			wantDeviation:  100.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BytesStats(tt.args.a, tt.args.b)
+			if got != tt.want {
+				t.Errorf("BytesStats() = %+v, want %+v", got, tt.want)
+			}
+			if similarity := got.Similarity(); math.Abs(similarity-tt.wantSimilarity) > 1e-9 {
+				t.Errorf("Stats.Similarity() = %v, want %v", similarity, tt.wantSimilarity)
+			}
+			deviation, err := got.Deviation()
+			if err != nil {
+				t.Fatalf("Stats.Deviation() error = %v", err)
+			}
+			if deviation.Get() != tt.wantDeviation {
+				t.Errorf("Stats.Deviation() = %v, want %v", deviation.Get(), tt.wantDeviation)
+			}
+		})
+	}
+}
+
+func TestStats_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats Stats
+		want  string
+	}{
+		{
+			name:  "no changes",
+			stats: Stats{Unchanged: 3},
+			want:  "0 insertions(+), 0 deletions(-)",
+		},
+		{
+			name:  "singular",
+			stats: Stats{Inserted: 1},
+			want:  "1 insertion(+)",
+		},
+		{
+			name:  "changed counts as insertion and deletion",
+			stats: Stats{Inserted: 1, Deleted: 2, Changed: 2},
+			want:  "3 insertions(+), 4 deletions(-)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.String(); got != tt.want {
+				t.Errorf("Stats.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats_StatLine(t *testing.T) {
+	type args struct {
+		name  string
+		width int
+	}
+	tests := []struct {
+		name  string
+		stats Stats
+		args  args
+		want  string
+	}{
+		{
+			name:  "unscaled",
+			stats: Stats{Inserted: 2, Changed: 1},
+			args:  args{name: "config.yaml", width: 0},
+			want:  "config.yaml | 4 +++-",
+		},
+		{
+			name:  "scaled",
+			stats: Stats{Inserted: 90, Deleted: 10},
+			args:  args{name: "a.bin", width: 10},
+			want:  "a.bin | 100 +++++++++-",
+		},
+		{
+			name:  "small share keeps one character",
+			stats: Stats{Inserted: 999, Deleted: 1},
+			args:  args{name: "a.bin", width: 10},
+			want:  "a.bin | 1000 +++++++++-",
+		},
+		{
+			name:  "no changes",
+			stats: Stats{Unchanged: 5},
+			args:  args{name: "a", width: 10},
+			want:  "a | 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.StatLine(tt.args.name, tt.args.width); got != tt.want {
+				t.Errorf("Stats.StatLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "abc", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "flaw", b: "lawn", want: 2},
+		{a: "abc", b: "abc", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein([]byte(tt.a), []byte(tt.b)); got != tt.want {
+				t.Errorf("Levenshtein() = %v, want %v", got, tt.want)
+			}
+			if got := Levenshtein([]byte(tt.b), []byte(tt.a)); got != tt.want {
+				t.Errorf("Levenshtein() swapped = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHamming(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		want    int
+		wantErr error
+	}{
+		{name: "equal", a: "abc", b: "abc", want: 0, wantErr: nil},
+		{name: "different", a: "karolin", b: "kathrin", want: 3, wantErr: nil},
+		{name: "different length", a: "ab", b: "abc", want: 0, wantErr: ErrLengthMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Hamming([]byte(tt.a), []byte(tt.b))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Hamming() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Hamming() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// DefaultStreamChunkSize defines the number of bytes read from every reader at once by default.
+const DefaultStreamChunkSize = 64 * 1024
+
+// StreamOptions configures the comparison of readers.
+type StreamOptions struct {
+	// ChunkSize defines the number of bytes read from every reader at once.
+	// Memory usage is bounded by two chunks. If 0, DefaultStreamChunkSize is used.
+	ChunkSize int
+}
+
+// chunkSize returns the configured chunk size or DefaultStreamChunkSize.
+func (o StreamOptions) chunkSize() int {
+	if o.ChunkSize <= 0 {
+		return DefaultStreamChunkSize
+	}
+	return o.ChunkSize
+}
+
+// ReadersEqual reports whether a and b provide the same bytes.
+// The readers are consumed chunk by chunk and reading stops at the first difference.
+func ReadersEqual(a, b io.Reader, opts StreamOptions) (bool, error) {
+	size := opts.chunkSize()
+	bufA, bufB := make([]byte, size), make([]byte, size)
+	for {
+		na, nb, err := readChunks(a, b, bufA, bufB)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if na == 0 {
+			return true, nil
+		}
+	}
+}
+
+// StreamDiff compares a and b index by index like BytesDifferent without reading them into memory.
+// Every report is passed to fn as soon as it is found, reports are ordered by index.
+// If fn returns an error, the comparison stops and the error is returned.
+func StreamDiff(a, b io.Reader, opts StreamOptions, fn func(Report) error) error {
+	size := opts.chunkSize()
+	bufA, bufB := make([]byte, size), make([]byte, size)
+	typ := reflect.TypeOf(byte(0)).String()
+	for offset := 0; ; {
+		na, nb, err := readChunks(a, b, bufA, bufB)
+		if err != nil {
+			return err
+		}
+		if na == 0 && nb == 0 {
+			return nil
+		}
+		for i := 0; i < na || i < nb; i++ {
+			report := Report{Type: typ, Index: offset + i}
+			switch {
+			case i >= na:
+				report.Kind, report.New = ReportKindInserted, bufB[i]
+			case i >= nb:
+				orig := bufA[i]
+				report.Kind, report.Original = ReportKindDeleted, &orig
+			case bufA[i] != bufB[i]:
+				orig := bufA[i]
+				report.Kind, report.Original, report.New = ReportKindChanged, &orig, bufB[i]
+			default:
+				continue
+			}
+			if err := fn(report); err != nil {
+				return err
+			}
+		}
+		offset += maxInt(na, nb)
+	}
+}
+
+// readChunks fills bufA from a and bufB from b and returns the number of bytes read.
+// A reader only returns less than a full chunk at its end.
+func readChunks(a, b io.Reader, bufA, bufB []byte) (int, int, error) {
+	na, err := readChunk(a, bufA)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading a: %w", err)
+	}
+	nb, err := readChunk(b, bufB)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading b: %w", err)
+	}
+	return na, nb, nil
+}
+
+// readChunk reads until buf is full or r is exhausted.
+func readChunk(r io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, nil
+	}
+	return n, err
+}
+
+// maxInt returns the larger of a and b.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadersEqual(t *testing.T) {
+	errRead := errors.New("read failed")
+	type args struct {
+		a    io.Reader
+		b    io.Reader
+		opts StreamOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "empty",
+			args: args{a: strings.NewReader(""), b: strings.NewReader("")},
+			want: true,
+		},
+		{
+			name: "equal across chunks",
+			args: args{a: strings.NewReader("abcdefgh"), b: iotest.OneByteReader(strings.NewReader("abcdefgh")), opts: StreamOptions{ChunkSize: 3}},
+			want: true,
+		},
+		{
+			name: "different",
+			args: args{a: strings.NewReader("abcdefgh"), b: strings.NewReader("abcdefgX"), opts: StreamOptions{ChunkSize: 3}},
+			want: false,
+		},
+		{
+			name: "prefix",
+			args: args{a: strings.NewReader("abc"), b: strings.NewReader("abcd"), opts: StreamOptions{ChunkSize: 3}},
+			want: false,
+		},
+		{
+			name: "stops at first difference",
+			args: args{
+				a:    io.MultiReader(strings.NewReader("abX"), iotest.ErrReader(errRead)),
+				b:    io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errRead)),
+				opts: StreamOptions{ChunkSize: 3},
+			},
+			want: false,
+		},
+		{
+			name: "read error",
+			args: args{
+				a:    io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errRead)),
+				b:    strings.NewReader("abcdef"),
+				opts: StreamOptions{ChunkSize: 3},
+			},
+			wantErr: errRead,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadersEqual(tt.args.a, tt.args.b, tt.args.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadersEqual() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadersEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamDiff(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := make([]byte, random.Intn(40))
+		random.Read(a)
+		b := append([]byte{}, a[:random.Intn(len(a)+1)]...)
+		for k := random.Intn(4); k > 0 && len(b) > 0; k-- {
+			b[random.Intn(len(b))] = byte(random.Intn(256))
+		}
+		tail := make([]byte, random.Intn(10))
+		random.Read(tail)
+		b = append(b, tail...)
+
+		want, err := BytesDifferent(a, b)
+		if err != nil {
+			t.Fatalf("BytesDifferent() error = %v", err)
+		}
+		got := Reports{}
+		opts := StreamOptions{ChunkSize: 1 + random.Intn(8)}
+		err = StreamDiff(bytes.NewReader(a), iotest.HalfReader(bytes.NewReader(b)), opts, func(report Report) error {
+			got = append(got, report)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamDiff() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("StreamDiff(%v, %v) = %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func TestStreamDiff_callbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	err := StreamDiff(strings.NewReader("aaaa"), strings.NewReader("bbbb"), StreamOptions{}, func(Report) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("StreamDiff() error = %v, want %v", err, errStop)
+	}
+	if calls != 1 {
+		t.Errorf("StreamDiff() called fn %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,139 @@
+package compare
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextPosition defines the location of a character in a text.
+type TextPosition struct {
+	// ByteOffset defines the 0-based offset in bytes.
+	ByteOffset int `json:"byteOffset"`
+	// RuneOffset defines the 0-based offset in runes.
+	RuneOffset int `json:"runeOffset"`
+	// Line defines the 1-based line number.
+	Line int `json:"line"`
+	// Column defines the 1-based column in runes.
+	Column int `json:"column"`
+}
+
+// TextReports defines a list of differences between two texts.
+type TextReports []TextReport
+
+// TextReport describes a difference between two texts.
+// A character is a grapheme-like cluster: a base rune together with its combining
+// marks, variation selectors, emoji modifiers and zero width joiner sequences.
+// Regional indicator pairs (flags) and CRLF are single characters as well.
+type TextReport struct {
+	// Kind defines whether the character was changed, inserted or deleted.
+	Kind ReportKind `json:"kind"`
+	// Original defines the character in A. Original is empty if the character was inserted.
+	Original string `json:"original"`
+	// New defines the character in B. New is empty if the character was deleted.
+	New string `json:"new"`
+	// OriginalPosition defines the position of the character in A.
+	// For inserted characters it is the position in A the character is inserted at.
+	OriginalPosition TextPosition `json:"originalPosition"`
+	// NewPosition defines the position of the character in B.
+	// For deleted characters it is the position in B the character was deleted at.
+	NewPosition TextPosition `json:"newPosition"`
+}
+
+// DiffText returns the difference between the texts a and b character by character.
+// Invalid UTF-8 sequences are treated as single characters.
+func DiffText(a, b string) TextReports {
+	aChars, aPos := splitCharacters(a)
+	bChars, bPos := splitCharacters(b)
+	script := DiffSequence(len(aChars), len(bChars), func(i, j int) bool {
+		return aChars[i] == bChars[j]
+	})
+	reports := TextReports{}
+	script.walkReports(func(kind ReportKind, i, j int) {
+		report := TextReport{
+			Kind:             kind,
+			OriginalPosition: aPos[i],
+			NewPosition:      bPos[j],
+		}
+		if kind != ReportKindInserted {
+			report.Original = aChars[i]
+		}
+		if kind != ReportKindDeleted {
+			report.New = bChars[j]
+		}
+		reports = append(reports, report)
+	})
+	return reports
+}
+
+// splitCharacters splits s into grapheme-like clusters and returns the clusters and
+// their positions. The positions contain an additional entry for the end of the text.
+func splitCharacters(s string) ([]string, []TextPosition) {
+	chars := []string{}
+	positions := []TextPosition{}
+	pos := TextPosition{Line: 1, Column: 1}
+	for len(s) > 0 {
+		n := clusterLength(s)
+		cluster := s[:n]
+		chars = append(chars, cluster)
+		positions = append(positions, pos)
+
+		runes := utf8.RuneCountInString(cluster)
+		pos.ByteOffset += n
+		pos.RuneOffset += runes
+		if cluster == "\n" || cluster == "\r\n" {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column += runes
+		}
+		s = s[n:]
+	}
+	positions = append(positions, pos)
+	return chars, positions
+}
+
+// clusterLength returns the length in bytes of the grapheme-like cluster at the start of s.
+func clusterLength(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && len(s) > 1 && s[1] == '\n' {
+		return 2
+	}
+	if isRegionalIndicator(r) {
+		if next, m := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(next) {
+			return n + m
+		}
+		return n
+	}
+	for n < len(s) {
+		next, m := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isExtending(next):
+			n += m
+		case next == zeroWidthJoiner:
+			n += m
+			// the joiner binds the following rune to the cluster
+			if n < len(s) {
+				_, k := utf8.DecodeRuneInString(s[n:])
+				n += k
+			}
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// zeroWidthJoiner joins emoji into a single character.
+const zeroWidthJoiner = '\u200d'
+
+// isExtending reports whether r extends the preceding character.
+func isExtending(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0xfe00 && r <= 0xfe0f) || // variation selectors
+		(r >= 0x1f3fb && r <= 0x1f3ff) // emoji skin tone modifiers
+}
+
+// isRegionalIndicator reports whether r is a regional indicator symbol used for flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
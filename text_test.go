@@ -0,0 +1,148 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffText(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name string
+		args args
+		want TextReports
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: "grüße",
+				b: "grüße",
+			},
+			want: TextReports{},
+		},
+		{
+			name: "changed umlaut is a single difference",
+			args: args{
+				a: "grün",
+				b: "grun",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindChanged,
+					Original:         "ü",
+					New:              "u",
+					OriginalPosition: TextPosition{ByteOffset: 2, RuneOffset: 2, Line: 1, Column: 3},
+					NewPosition:      TextPosition{ByteOffset: 2, RuneOffset: 2, Line: 1, Column: 3},
+				},
+			},
+		},
+		{
+			name: "inserted character after multi-byte characters",
+			args: args{
+				a: "äö",
+				b: "äöü",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindInserted,
+					Original:         "",
+					New:              "ü",
+					OriginalPosition: TextPosition{ByteOffset: 4, RuneOffset: 2, Line: 1, Column: 3},
+					NewPosition:      TextPosition{ByteOffset: 4, RuneOffset: 2, Line: 1, Column: 3},
+				},
+			},
+		},
+		{
+			name: "deleted character on second line",
+			args: args{
+				a: "first\nsecønd",
+				b: "first\nsecnd",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindDeleted,
+					Original:         "ø",
+					New:              "",
+					OriginalPosition: TextPosition{ByteOffset: 9, RuneOffset: 9, Line: 2, Column: 4},
+					NewPosition:      TextPosition{ByteOffset: 9, RuneOffset: 9, Line: 2, Column: 4},
+				},
+			},
+		},
+		{
+			name: "combining mark belongs to its base character",
+			args: args{
+				a: "cafe\u0301",
+				b: "cafe",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindChanged,
+					Original:         "e\u0301",
+					New:              "e",
+					OriginalPosition: TextPosition{ByteOffset: 3, RuneOffset: 3, Line: 1, Column: 4},
+					NewPosition:      TextPosition{ByteOffset: 3, RuneOffset: 3, Line: 1, Column: 4},
+				},
+			},
+		},
+		{
+			name: "emoji sequences and flags",
+			args: args{
+				a: "👩\u200d💻🇩🇪",
+				b: "👩\u200d💻🇫🇷",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindChanged,
+					Original:         "🇩🇪",
+					New:              "🇫🇷",
+					OriginalPosition: TextPosition{ByteOffset: 11, RuneOffset: 3, Line: 1, Column: 4},
+					NewPosition:      TextPosition{ByteOffset: 11, RuneOffset: 3, Line: 1, Column: 4},
+				},
+			},
+		},
+		{
+			name: "crlf is a single character",
+			args: args{
+				a: "a\r\nb",
+				b: "a\nb",
+			},
+			want: TextReports{
+				{
+					Kind:             ReportKindChanged,
+					Original:         "\r\n",
+					New:              "\n",
+					OriginalPosition: TextPosition{ByteOffset: 1, RuneOffset: 1, Line: 1, Column: 2},
+					NewPosition:      TextPosition{ByteOffset: 1, RuneOffset: 1, Line: 1, Column: 2},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffText(tt.args.a, tt.args.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffText() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitCharacters(t *testing.T) {
+	chars, positions := splitCharacters("ü\nx")
+	wantChars := []string{"ü", "\n", "x"}
+	wantPositions := []TextPosition{
+		{ByteOffset: 0, RuneOffset: 0, Line: 1, Column: 1},
+		{ByteOffset: 2, RuneOffset: 1, Line: 1, Column: 2},
+		{ByteOffset: 3, RuneOffset: 2, Line: 2, Column: 1},
+		{ByteOffset: 4, RuneOffset: 3, Line: 2, Column: 2},
+	}
+	if !reflect.DeepEqual(chars, wantChars) {
+		t.Errorf("splitCharacters() chars = %q, want %q", chars, wantChars)
+	}
+	if !reflect.DeepEqual(positions, wantPositions) {
+		t.Errorf("splitCharacters() positions = %+v, want %+v", positions, wantPositions)
+	}
+}
@@ -0,0 +1,334 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidUnifiedDiff is returned when a unified diff cannot be parsed.
+	ErrInvalidUnifiedDiff = errors.New("invalid unified diff")
+)
+
+// DefaultContextLines defines the number of context lines used by diff and patch by default.
+const DefaultContextLines = 3
+
+// noNewlineMarker is written after a line that is not terminated by a newline.
+const noNewlineMarker = `\ No newline at end of file`
+
+// SplitLines splits s into lines. Every line keeps its "\n" terminator, the last
+// line has no terminator if s does not end with a newline.
+func SplitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// DiffLines returns the difference between the lines of a and b.
+func DiffLines(a, b string) SequenceReports {
+	return DiffStrings(SplitLines(a), SplitLines(b))
+}
+
+// UnifiedOptions configures the unified diff output.
+type UnifiedOptions struct {
+	// FromFile defines the name of the original file written to the "---" header.
+	FromFile string
+	// ToFile defines the name of the new file written to the "+++" header.
+	ToFile string
+	// Context defines the number of unchanged lines written around every change.
+	// Use DefaultContextLines for the default of diff and patch.
+	Context int
+}
+
+// UnifiedDiff defines the structured form of a unified diff of a single file.
+type UnifiedDiff struct {
+	// FromFile defines the name of the original file.
+	FromFile string `json:"fromFile"`
+	// ToFile defines the name of the new file.
+	ToFile string `json:"toFile"`
+	// Hunks defines the changed regions of the file.
+	Hunks []UnifiedHunk `json:"hunks"`
+}
+
+// UnifiedHunk defines a contiguous region of changes and its context lines.
+type UnifiedHunk struct {
+	// FromLine defines the 1-based start line in the original file.
+	// If FromCount is 0, FromLine is the line after which lines are inserted.
+	FromLine int `json:"fromLine"`
+	// FromCount defines the number of lines of the original file in this hunk.
+	FromCount int `json:"fromCount"`
+	// ToLine defines the 1-based start line in the new file.
+	// If ToCount is 0, ToLine is the line after which lines were deleted.
+	ToLine int `json:"toLine"`
+	// ToCount defines the number of lines of the new file in this hunk.
+	ToCount int `json:"toCount"`
+	// Lines defines the context, deleted and inserted lines of the hunk.
+	Lines []UnifiedLine `json:"lines"`
+}
+
+// UnifiedLine defines a single line of a hunk.
+type UnifiedLine struct {
+	// Op defines whether the line is context (equal), deleted or inserted.
+	Op EditOp `json:"op"`
+	// Text defines the content of the line without the "\n" terminator.
+	// The "\r" of a CRLF line is part of the text.
+	Text string `json:"text"`
+	// NoNewline is true if the line is the last line of its file and not terminated by a newline.
+	NoNewline bool `json:"noNewline,omitempty"`
+}
+
+// NewUnifiedDiff returns the structured unified diff of a and b.
+func NewUnifiedDiff(a, b string, opts UnifiedOptions) UnifiedDiff {
+	aLines, bLines := SplitLines(a), SplitLines(b)
+	script := DiffSequence(len(aLines), len(bLines), func(i, j int) bool {
+		return aLines[i] == bLines[j]
+	})
+	diff := UnifiedDiff{
+		FromFile: opts.FromFile,
+		ToFile:   opts.ToFile,
+	}
+
+	context := opts.Context
+	if context < 0 {
+		context = 0
+	}
+	type lineOp struct {
+		op   EditOp
+		a, b int
+	}
+	ops := []lineOp{}
+	for _, edit := range script {
+		switch edit.Op {
+		case EditEqual:
+			for k := 0; k < edit.AEnd-edit.AStart; k++ {
+				ops = append(ops, lineOp{op: EditEqual, a: edit.AStart + k, b: edit.BStart + k})
+			}
+		case EditDelete:
+			for k := edit.AStart; k < edit.AEnd; k++ {
+				ops = append(ops, lineOp{op: EditDelete, a: k, b: edit.BStart})
+			}
+		case EditInsert:
+			for k := edit.BStart; k < edit.BEnd; k++ {
+				ops = append(ops, lineOp{op: EditInsert, a: edit.AStart, b: k})
+			}
+		}
+	}
+
+	for i := 0; i < len(ops); i++ {
+		if ops[i].op == EditEqual {
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// extend the hunk while the next change is close enough to share the context
+		end, equalRun := i, 0
+		for j := i; j < len(ops) && equalRun <= 2*context; j++ {
+			if ops[j].op == EditEqual {
+				equalRun++
+				continue
+			}
+			equalRun = 0
+			end = j
+		}
+		stop := end + context + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		hunk := UnifiedHunk{}
+		hunk.FromLine, hunk.ToLine = ops[start].a+1, ops[start].b+1
+		for _, op := range ops[start:stop] {
+			line := UnifiedLine{Op: op.op}
+			switch op.op {
+			case EditEqual, EditDelete:
+				line.Text, line.NoNewline = trimNewline(aLines[op.a])
+				hunk.FromCount++
+				if op.op == EditEqual {
+					hunk.ToCount++
+				}
+			case EditInsert:
+				line.Text, line.NoNewline = trimNewline(bLines[op.b])
+				hunk.ToCount++
+			}
+			hunk.Lines = append(hunk.Lines, line)
+		}
+		if hunk.FromCount == 0 {
+			hunk.FromLine--
+		}
+		if hunk.ToCount == 0 {
+			hunk.ToLine--
+		}
+		diff.Hunks = append(diff.Hunks, hunk)
+		i = stop - 1
+	}
+	return diff
+}
+
+// Unified returns the unified diff text of a and b.
+// An empty string is returned if a and b are equal.
+func Unified(a, b string, opts UnifiedOptions) string {
+	return NewUnifiedDiff(a, b, opts).String()
+}
+
+// trimNewline removes the line terminator and reports whether the line had none.
+func trimNewline(line string) (string, bool) {
+	if strings.HasSuffix(line, "\n") {
+		return strings.TrimSuffix(line, "\n"), false
+	}
+	return line, true
+}
+
+// String returns the unified diff text. An empty string is returned if the diff has no hunks.
+func (u UnifiedDiff) String() string {
+	if len(u.Hunks) == 0 {
+		return ""
+	}
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", u.FromFile, u.ToFile)
+	for _, hunk := range u.Hunks {
+		sb.WriteString(hunk.Header())
+		sb.WriteByte('\n')
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case EditEqual:
+				sb.WriteByte(' ')
+			case EditDelete:
+				sb.WriteByte('-')
+			case EditInsert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+			if line.NoNewline {
+				sb.WriteString(noNewlineMarker)
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Header returns the "@@ -l,s +l,s @@" header of the hunk.
+func (h UnifiedHunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", unifiedRange(h.FromLine, h.FromCount), unifiedRange(h.ToLine, h.ToCount))
+}
+
+// unifiedRange formats a line range of a hunk header. The count is omitted if it is 1.
+func unifiedRange(line, count int) string {
+	if count == 1 {
+		return strconv.Itoa(line)
+	}
+	return strconv.Itoa(line) + "," + strconv.Itoa(count)
+}
+
+// hunkHeaderRegex matches a hunk header, the section heading after the second "@@" is ignored.
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff parses unified diff text into its structured form.
+// The text may contain the diffs of several files. Lines before a "---" header,
+// e.g. "diff --git" or "index" lines, are ignored.
+func ParseUnifiedDiff(text string) ([]UnifiedDiff, error) {
+	// lines are only split at "\n", the "\r" of CRLF lines is part of their text
+	lines := strings.Split(text, "\n")
+	diffs := []UnifiedDiff{}
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return nil, fmt.Errorf("%w: line %d: missing +++ header", ErrInvalidUnifiedDiff, i+2)
+		}
+		diff := UnifiedDiff{
+			FromFile: parseFileName(lines[i][4:]),
+			ToFile:   parseFileName(lines[i+1][4:]),
+		}
+		i += 2
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@ ") {
+			hunk, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			diff.Hunks = append(diff.Hunks, hunk)
+			i = next
+		}
+		i--
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// parseFileName removes the optional timestamp after a tab and a trailing "\r" from a file header.
+func parseFileName(header string) string {
+	header = strings.TrimSuffix(header, "\r")
+	if idx := strings.Index(header, "\t"); idx >= 0 {
+		return header[:idx]
+	}
+	return header
+}
+
+// parseHunk parses the hunk starting at lines[start] and returns the index of the line after the hunk.
+func parseHunk(lines []string, start int) (UnifiedHunk, int, error) {
+	m := hunkHeaderRegex.FindStringSubmatch(lines[start])
+	if m == nil {
+		return UnifiedHunk{}, 0, fmt.Errorf("%w: line %d: malformed hunk header %q", ErrInvalidUnifiedDiff, start+1, lines[start])
+	}
+	atoi := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	hunk := UnifiedHunk{
+		FromLine:  atoi(m[1]),
+		FromCount: atoi(m[2]),
+		ToLine:    atoi(m[3]),
+		ToCount:   atoi(m[4]),
+	}
+
+	fromLeft, toLeft := hunk.FromCount, hunk.ToCount
+	i := start + 1
+	for ; i < len(lines) && (fromLeft > 0 || toLeft > 0); i++ {
+		line := lines[i]
+		if line == "" {
+			// some tools strip the trailing space of empty context lines
+			line = " "
+		}
+		switch line[0] {
+		case ' ':
+			hunk.Lines = append(hunk.Lines, UnifiedLine{Op: EditEqual, Text: line[1:]})
+			fromLeft--
+			toLeft--
+		case '-':
+			hunk.Lines = append(hunk.Lines, UnifiedLine{Op: EditDelete, Text: line[1:]})
+			fromLeft--
+		case '+':
+			hunk.Lines = append(hunk.Lines, UnifiedLine{Op: EditInsert, Text: line[1:]})
+			toLeft--
+		case '\\':
+			if len(hunk.Lines) > 0 {
+				hunk.Lines[len(hunk.Lines)-1].NoNewline = true
+			}
+		default:
+			return UnifiedHunk{}, 0, fmt.Errorf("%w: line %d: unexpected line %q", ErrInvalidUnifiedDiff, i+1, lines[i])
+		}
+		if fromLeft < 0 || toLeft < 0 {
+			return UnifiedHunk{}, 0, fmt.Errorf("%w: line %d: hunk exceeds the line counts of its header", ErrInvalidUnifiedDiff, i+1)
+		}
+	}
+	if fromLeft > 0 || toLeft > 0 {
+		return UnifiedHunk{}, 0, fmt.Errorf("%w: hunk %q is incomplete", ErrInvalidUnifiedDiff, lines[start])
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], `\`) && len(hunk.Lines) > 0 {
+		hunk.Lines[len(hunk.Lines)-1].NoNewline = true
+		i++
+	}
+	return hunk, i, nil
+}
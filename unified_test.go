@@ -0,0 +1,303 @@
+package compare
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: []string{},
+		},
+		{
+			name: "terminated lines",
+			in:   "a\nb\n",
+			want: []string{"a\n", "b\n"},
+		},
+		{
+			name: "unterminated last line",
+			in:   "a\nb",
+			want: []string{"a\n", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitLines(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("a\nb\nc\n", "a\nB\nc\n")
+	want := SequenceReports{
+		{Type: "string", Kind: ReportKindChanged, Index: 1, Original: "b\n", New: "B\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	type args struct {
+		a    string
+		b    string
+		opts UnifiedOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "equal",
+			args: args{
+				a:    "a\nb\n",
+				b:    "a\nb\n",
+				opts: UnifiedOptions{FromFile: "a.txt", ToFile: "b.txt", Context: DefaultContextLines},
+			},
+			want: "",
+		},
+		{
+			name: "changed line with context",
+			args: args{
+				a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+				b:    "1\n2\n3\n4\nfive\n6\n7\n8\n",
+				opts: UnifiedOptions{FromFile: "a.txt", ToFile: "b.txt", Context: DefaultContextLines},
+			},
+			want: "--- a.txt\n" +
+				"+++ b.txt\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				"-5\n" +
+				"+five\n" +
+				" 6\n" +
+				" 7\n" +
+				" 8\n",
+		},
+		{
+			name: "distant changes produce separate hunks",
+			args: args{
+				a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+				b:    "one\n2\n3\n4\n5\n6\n7\neight\n",
+				opts: UnifiedOptions{FromFile: "a", ToFile: "b", Context: 1},
+			},
+			want: "--- a\n" +
+				"+++ b\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				"-1\n" +
+				"+one\n" +
+				" 2\n" +
+				"@@ -7,2 +7,2 @@\n" +
+				" 7\n" +
+				"-8\n" +
+				"+eight\n",
+		},
+		{
+			name: "close changes share a hunk",
+			args: args{
+				a:    "1\n2\n3\n4\n",
+				b:    "one\n2\n3\nfour\n",
+				opts: UnifiedOptions{FromFile: "a", ToFile: "b", Context: 1},
+			},
+			want: "--- a\n" +
+				"+++ b\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-1\n" +
+				"+one\n" +
+				" 2\n" +
+				" 3\n" +
+				"-4\n" +
+				"+four\n",
+		},
+		{
+			name: "insert without context",
+			args: args{
+				a:    "1\n2\n",
+				b:    "1\nnew\n2\n",
+				opts: UnifiedOptions{FromFile: "a", ToFile: "b", Context: 0},
+			},
+			want: "--- a\n" +
+				"+++ b\n" +
+				"@@ -1,0 +2 @@\n" +
+				"+new\n",
+		},
+		{
+			name: "new file",
+			args: args{
+				a:    "",
+				b:    "1\n2\n",
+				opts: UnifiedOptions{FromFile: "/dev/null", ToFile: "b", Context: DefaultContextLines},
+			},
+			want: "--- /dev/null\n" +
+				"+++ b\n" +
+				"@@ -0,0 +1,2 @@\n" +
+				"+1\n" +
+				"+2\n",
+		},
+		{
+			name: "missing newline at end of file",
+			args: args{
+				a:    "1\n2",
+				b:    "1\n2\n",
+				opts: UnifiedOptions{FromFile: "a", ToFile: "b", Context: DefaultContextLines},
+			},
+			want: "--- a\n" +
+				"+++ b\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				" 1\n" +
+				"-2\n" +
+				`\ No newline at end of file` + "\n" +
+				"+2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.args.a, tt.args.b, tt.args.opts); got != tt.want {
+				t.Errorf("Unified() = \n%s\n, want \n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []UnifiedDiff
+		wantErr error
+	}{
+		{
+			name: "git diff with two files",
+			in: "diff --git a/x b/x\n" +
+				"index 1234567..89abcde 100644\n" +
+				"--- a/x\t2022-03-05 10:00:00\n" +
+				"+++ b/x\t2022-03-05 11:00:00\n" +
+				"@@ -1,2 +1,2 @@ func main() {\n" +
+				" keep\n" +
+				"-old\n" +
+				`\ No newline at end of file` + "\n" +
+				"+new\n" +
+				"--- a/y\n" +
+				"+++ b/y\n" +
+				"@@ -0,0 +1 @@\n" +
+				"+added\n",
+			want: []UnifiedDiff{
+				{
+					FromFile: "a/x",
+					ToFile:   "b/x",
+					Hunks: []UnifiedHunk{
+						{
+							FromLine:  1,
+							FromCount: 2,
+							ToLine:    1,
+							ToCount:   2,
+							Lines: []UnifiedLine{
+								{Op: EditEqual, Text: "keep"},
+								{Op: EditDelete, Text: "old", NoNewline: true},
+								{Op: EditInsert, Text: "new"},
+							},
+						},
+					},
+				},
+				{
+					FromFile: "a/y",
+					ToFile:   "b/y",
+					Hunks: []UnifiedHunk{
+						{
+							FromLine:  0,
+							FromCount: 0,
+							ToLine:    1,
+							ToCount:   1,
+							Lines: []UnifiedLine{
+								{Op: EditInsert, Text: "added"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "missing +++ header",
+			in:      "--- a\n@@ -1 +1 @@\n-a\n+b\n",
+			want:    nil,
+			wantErr: ErrInvalidUnifiedDiff,
+		},
+		{
+			name:    "malformed hunk header",
+			in:      "--- a\n+++ b\n@@ -x +1 @@\n",
+			want:    nil,
+			wantErr: ErrInvalidUnifiedDiff,
+		},
+		{
+			name:    "incomplete hunk",
+			in:      "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n",
+			want:    nil,
+			wantErr: ErrInvalidUnifiedDiff,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnifiedDiff(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseUnifiedDiff() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUnifiedDiff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{
+			name: "lf",
+			a:    "package main\n\nfunc main() {\n\tprintln(\"a\")\n}\n",
+			b:    "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"b\")\n}",
+		},
+		{
+			name: "crlf",
+			a:    "[core]\r\n\tbare = false\r\n\tfilemode = true\r\n",
+			b:    "[core]\r\n\tbare = true\r\n\tfilemode = true\r\n",
+		},
+		{
+			name: "line endings changed",
+			a:    "a\r\nb\r\n",
+			b:    "a\nb\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := NewUnifiedDiff(tt.a, tt.b, UnifiedOptions{FromFile: "a/file", ToFile: "b/file", Context: DefaultContextLines})
+			text := diff.String()
+			parsed, err := ParseUnifiedDiff(text)
+			if err != nil {
+				t.Fatalf("ParseUnifiedDiff() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed, []UnifiedDiff{diff}) {
+				t.Errorf("ParseUnifiedDiff() = %+v, want %+v", parsed, []UnifiedDiff{diff})
+			}
+			if got := parsed[0].String(); got != text {
+				t.Errorf("UnifiedDiff.String() = %q, want %q", got, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValueReports defines a list of differences between two values.
+type ValueReports []ValueReport
+
+// ValueReport describes a difference between two values at a path.
+// Paths use the format of EqualOptions, e.g. "users[2].email". The root value has an empty path.
+// Struct fields use their json names, so structs and decoded JSON documents report the same paths.
+type ValueReport struct {
+	// Path defines the location of the difference.
+	Path string
+	// Kind is ReportKindInserted if the value was added in B, ReportKindDeleted if it was
+	// removed from A and ReportKindChanged if the value differs.
+	Kind ReportKind
+	// Old defines the value in A. Old is nil if the value was added.
+	Old interface{}
+	// New defines the value in B. New is nil if the value was removed.
+	New interface{}
+}
+
+// String returns the report in the format "users[2].email: a@x → b@x".
+func (r ValueReport) String() string {
+	path := r.Path
+	if path == "" {
+		path = "(root)"
+	}
+	switch r.Kind {
+	case ReportKindInserted:
+		return fmt.Sprintf("%s: added %v", path, r.New)
+	case ReportKindDeleted:
+		return fmt.Sprintf("%s: removed %v", path, r.Old)
+	}
+	return fmt.Sprintf("%s: %v → %v", path, r.Old, r.New)
+}
+
+// String returns one report per line.
+func (r ValueReports) String() string {
+	lines := make([]string, len(r))
+	for i, report := range r {
+		lines[i] = report.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffValues returns the differences between a and b.
+// Structs, maps, slices, arrays, pointers and interfaces are walked recursively,
+// all other values are compared like reflect.DeepEqual does.
+// Map keys are visited in sorted order and slices are aligned with the shortest edit script,
+// so an inserted element is reported once instead of changing all following elements.
+// Reference cycles are followed once.
+func DiffValues(a, b interface{}) ValueReports {
+	d := &valueDiff{
+		reports: ValueReports{},
+		visited: map[equalVisit]bool{},
+		leaf:    newEqualState(EqualOptions{}),
+	}
+	d.diff("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.reports
+}
+
+// valueDiff holds the state of a single structural diff.
+type valueDiff struct {
+	reports ValueReports
+	visited map[equalVisit]bool
+	leaf    *equalState
+}
+
+// add appends a report for the values at path.
+func (d *valueDiff) add(path string, kind ReportKind, a, b reflect.Value) {
+	report := ValueReport{Path: path, Kind: kind}
+	if kind != ReportKindInserted {
+		report.Old = valueInterface(a)
+	}
+	if kind != ReportKindDeleted {
+		report.New = valueInterface(b)
+	}
+	d.reports = append(d.reports, report)
+}
+
+// visit runs fn unless the pair of references is already being walked further up the stack.
+func (d *valueDiff) visit(a, b reflect.Value, fn func()) {
+	v := equalVisit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+	if d.visited[v] {
+		return
+	}
+	d.visited[v] = true
+	defer delete(d.visited, v)
+	fn()
+}
+
+// diff compares a and b located at path.
+func (d *valueDiff) diff(path string, a, b reflect.Value) {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return
+	case !a.IsValid():
+		d.add(path, ReportKindInserted, a, b)
+		return
+	case !b.IsValid():
+		d.add(path, ReportKindDeleted, a, b)
+		return
+	case a.Type() != b.Type():
+		d.add(path, ReportKindChanged, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.Pointer() == b.Pointer() {
+			return
+		}
+		if a.IsNil() || b.IsNil() {
+			d.add(path, ReportKindChanged, a, b)
+			return
+		}
+		d.visit(a, b, func() { d.diff(path, a.Elem(), b.Elem()) })
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, ReportKindChanged, a, b)
+			}
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			d.diff(joinPath(path, jsonFieldName(a.Type().Field(i))), a.Field(i), b.Field(i))
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			d.add(path, ReportKindChanged, a, b)
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			return
+		}
+		d.visit(a, b, func() { d.diffMap(path, a, b) })
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			d.add(path, ReportKindChanged, a, b)
+			return
+		}
+		if a.Len() == b.Len() && (a.Len() == 0 || a.Pointer() == b.Pointer()) {
+			return
+		}
+		d.visit(a, b, func() { d.diffSequence(path, a, b) })
+	case reflect.Array:
+		d.diffSequence(path, a, b)
+	default:
+		if !d.leaf.equal("", a, b) {
+			d.add(path, ReportKindChanged, a, b)
+		}
+	}
+}
+
+// diffMap compares the entries of the maps a and b in sorted key order.
+func (d *valueDiff) diffMap(path string, a, b reflect.Value) {
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sortValues(keys)
+	for _, key := range keys {
+		d.diff(joinPath(path, fmt.Sprint(key)), a.MapIndex(key), b.MapIndex(key))
+	}
+}
+
+// diffSequence aligns the elements of the slices or arrays a and b and compares
+// the elements that were changed in place.
+func (d *valueDiff) diffSequence(path string, a, b reflect.Value) {
+	script := DiffSequence(a.Len(), b.Len(), func(i, j int) bool {
+		return d.leaf.equal("", a.Index(i), b.Index(j))
+	})
+	script.walkReports(func(kind ReportKind, i, j int) {
+		switch kind {
+		case ReportKindChanged:
+			d.diff(indexPath(path, i), a.Index(i), b.Index(j))
+		case ReportKindDeleted:
+			d.add(indexPath(path, i), kind, a.Index(i), reflect.Value{})
+		case ReportKindInserted:
+			d.add(indexPath(path, j), kind, reflect.Value{}, b.Index(j))
+		}
+	})
+}
+
+// valueInterface returns the value held by v. Values of unexported fields are
+// returned in their formatted form because they cannot be accessed directly.
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprint(v)
+}
+
+// sortValues sorts map keys: numbers numerically, strings and all other values by their formatted form.
+func sortValues(values []reflect.Value) {
+	sort.SliceStable(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if a.Kind() == b.Kind() {
+			switch a.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			case reflect.String:
+				return a.String() < b.String()
+			}
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+}
@@ -0,0 +1,208 @@
+package compare
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type valueUser struct {
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Tags  []string `json:"tags,omitempty"`
+	next  *valueUser
+}
+
+type valueNode struct {
+	Value int
+	Next  *valueNode
+}
+
+func TestDiffValues(t *testing.T) {
+	type args struct {
+		a interface{}
+		b interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+		want ValueReports
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: valueUser{Name: "a", Tags: []string{"x"}},
+				b: valueUser{Name: "a", Tags: []string{"x"}},
+			},
+			want: ValueReports{},
+		},
+		{
+			name: "changed root",
+			args: args{
+				a: 1,
+				b: 2,
+			},
+			want: ValueReports{
+				{Path: "", Kind: ReportKindChanged, Old: 1, New: 2},
+			},
+		},
+		{
+			name: "different types",
+			args: args{
+				a: 1,
+				b: "1",
+			},
+			want: ValueReports{
+				{Path: "", Kind: ReportKindChanged, Old: 1, New: "1"},
+			},
+		},
+		{
+			name: "nil and value",
+			args: args{
+				a: nil,
+				b: "x",
+			},
+			want: ValueReports{
+				{Path: "", Kind: ReportKindInserted, Old: nil, New: "x"},
+			},
+		},
+		{
+			name: "struct fields and slices",
+			args: args{
+				a: []valueUser{{Name: "a", Email: "a@x"}, {Name: "b", Email: "b@x", Tags: []string{"admin"}}},
+				b: []valueUser{{Name: "a", Email: "a@x"}, {Name: "b", Email: "b@y", Tags: []string{"admin", "ops"}}},
+			},
+			want: ValueReports{
+				{Path: "[1].email", Kind: ReportKindChanged, Old: "b@x", New: "b@y"},
+				{Path: "[1].tags[1]", Kind: ReportKindInserted, Old: nil, New: "ops"},
+			},
+		},
+		{
+			name: "unexported fields are reported in their formatted form",
+			args: args{
+				a: valueUser{next: nil},
+				b: valueUser{next: &valueUser{}},
+			},
+			want: ValueReports{
+				{Path: "next", Kind: ReportKindChanged, Old: "<nil>", New: "&{  [] <nil>}"},
+			},
+		},
+		{
+			name: "inserted slice element does not change the following elements",
+			args: args{
+				a: []int{1, 2, 3},
+				b: []int{0, 1, 2, 3},
+			},
+			want: ValueReports{
+				{Path: "[0]", Kind: ReportKindInserted, Old: nil, New: 0},
+			},
+		},
+		{
+			name: "map keys are sorted",
+			args: args{
+				a: map[int]string{10: "a", 2: "b", 1: "c"},
+				b: map[int]string{10: "x", 2: "b", 3: "d"},
+			},
+			want: ValueReports{
+				{Path: "1", Kind: ReportKindDeleted, Old: "c", New: nil},
+				{Path: "3", Kind: ReportKindInserted, Old: nil, New: "d"},
+				{Path: "10", Kind: ReportKindChanged, Old: "a", New: "x"},
+			},
+		},
+		{
+			name: "nil and empty slice",
+			args: args{
+				a: []int(nil),
+				b: []int{},
+			},
+			want: ValueReports{
+				{Path: "", Kind: ReportKindChanged, Old: []int(nil), New: []int{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffValues(tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffValues() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffValues_JSON(t *testing.T) {
+	var a, b interface{}
+	if err := json.Unmarshal([]byte(`{"users":[{"name":"a","email":"a@x"},{"name":"b","email":"b@x"},{"name":"c","email":"a@x"}]}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"users":[{"name":"a","email":"a@x"},{"name":"b","email":"b@x"},{"name":"c","email":"b@x"}],"total":3}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	got := DiffValues(a, b).String()
+	want := "total: added 3\n" +
+		"users[2].email: a@x → b@x"
+	if got != want {
+		t.Errorf("DiffValues() = %q, want %q", got, want)
+	}
+
+	// structs report the json names of their fields like the decoded documents
+	type document struct {
+		Users []valueUser `json:"users"`
+		Total int         `json:"total,omitempty"`
+	}
+	users := []valueUser{{Name: "a", Email: "a@x"}, {Name: "b", Email: "b@x"}, {Name: "c", Email: "a@x"}}
+	changed := append([]valueUser{}, users...)
+	changed[2].Email = "b@x"
+	got = DiffValues(document{Users: users}, document{Users: changed}).String()
+	if want := "users[2].email: a@x → b@x"; got != want {
+		t.Errorf("DiffValues() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffValues_Cycle(t *testing.T) {
+	a := &valueNode{Value: 1}
+	a.Next = &valueNode{Value: 2, Next: a}
+	b := &valueNode{Value: 1}
+	b.Next = &valueNode{Value: 3, Next: b}
+	want := ValueReports{
+		{Path: "Next.Value", Kind: ReportKindChanged, Old: 2, New: 3},
+	}
+	if got := DiffValues(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffValues() = %+v, want %+v", got, want)
+	}
+}
+
+func TestValueReport_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		report ValueReport
+		want   string
+	}{
+		{
+			name:   "changed",
+			report: ValueReport{Path: "users[2].email", Kind: ReportKindChanged, Old: "a@x", New: "b@x"},
+			want:   "users[2].email: a@x → b@x",
+		},
+		{
+			name:   "added",
+			report: ValueReport{Path: "tags[0]", Kind: ReportKindInserted, New: "x"},
+			want:   "tags[0]: added x",
+		},
+		{
+			name:   "removed",
+			report: ValueReport{Path: "id", Kind: ReportKindDeleted, Old: 7},
+			want:   "id: removed 7",
+		},
+		{
+			name:   "root",
+			report: ValueReport{Kind: ReportKindChanged, Old: 1, New: 2},
+			want:   "(root): 1 → 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.String(); got != tt.want {
+				t.Errorf("ValueReport.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}